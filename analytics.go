@@ -0,0 +1,254 @@
+package go_groshi
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// recurringAmountTolerance is the maximum relative difference between an amount and the typical amount
+	// for a transaction to be considered the same recurring payment.
+	recurringAmountTolerance = 0.2
+
+	// minMonthlyInterval and maxMonthlyInterval bound the interval between recurring payments of monthly cadence.
+	minMonthlyInterval = 25 * 24 * time.Hour
+	maxMonthlyInterval = 35 * 24 * time.Hour
+)
+
+// RecurringPattern represents a series of similar transactions repeated on a roughly monthly cadence.
+type RecurringPattern struct {
+	Description   string // description of the most recent transaction of the series
+	Currency      string
+	TypicalAmount int           // median amount of the series
+	Cadence       time.Duration // median interval between transactions of the series
+	LastSeen      time.Time     // timestamp of the most recent transaction of the series
+}
+
+// normalizeDescription strips digits, punctuation and case from description,
+// so that descriptions like "Netflix #1234" and "netflix #1299" are considered equal.
+func normalizeDescription(description string) string {
+	fields := strings.FieldsFunc(strings.ToLower(description), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	return strings.Join(fields, " ")
+}
+
+// medianInt returns median of the values. The values slice is sorted in place.
+func medianInt(values []int) int {
+	sort.Ints(values)
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[middle-1] + values[middle]) / 2
+	}
+	return values[middle]
+}
+
+// medianDuration returns median of the values. The values slice is sorted in place.
+func medianDuration(values []time.Duration) time.Duration {
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	middle := len(values) / 2
+	if len(values)%2 == 0 {
+		return (values[middle-1] + values[middle]) / 2
+	}
+	return values[middle]
+}
+
+// DetectRecurring finds likely recurring payments (rent, subscriptions, salary) among the transactions.
+// Transactions are grouped by currency and description (ignoring case, digits and punctuation);
+// within each group, transactions whose amounts differ from the median by more than 20% are ignored.
+// A group is reported if at least minOccurrences of its transactions remain and the intervals
+// between them are roughly monthly (25 to 35 days). Patterns are sorted by description.
+func DetectRecurring(ts []*Transaction, minOccurrences int) []RecurringPattern {
+	if minOccurrences < 2 {
+		minOccurrences = 2 // cadence cannot be determined from a single transaction
+	}
+
+	type groupKey struct {
+		currency    string
+		description string
+	}
+	groups := make(map[groupKey][]*Transaction)
+	for _, transaction := range ts {
+		key := groupKey{transaction.Currency, normalizeDescription(transaction.Description)}
+		if key.description == "" {
+			continue
+		}
+		groups[key] = append(groups[key], transaction)
+	}
+
+	patterns := make([]RecurringPattern, 0)
+	for key, group := range groups {
+		if len(group) < minOccurrences {
+			continue
+		}
+
+		amounts := make([]int, len(group))
+		for i, transaction := range group {
+			amounts[i] = transaction.Amount
+		}
+		typicalAmount := medianInt(amounts)
+
+		similar := make([]*Transaction, 0, len(group))
+		for _, transaction := range group {
+			difference := abs(transaction.Amount - typicalAmount)
+			if float64(difference) <= recurringAmountTolerance*float64(abs(typicalAmount)) {
+				similar = append(similar, transaction)
+			}
+		}
+		if len(similar) < minOccurrences {
+			continue
+		}
+		sort.Slice(similar, func(i, j int) bool {
+			return similar[i].Timestamp.Before(similar[j].Timestamp)
+		})
+
+		intervals := make([]time.Duration, 0, len(similar)-1)
+		for i := 1; i < len(similar); i++ {
+			interval := similar[i].Timestamp.Sub(similar[i-1].Timestamp)
+			if interval < minMonthlyInterval || interval > maxMonthlyInterval {
+				continue
+			}
+			intervals = append(intervals, interval)
+		}
+		if len(intervals) < minOccurrences-1 {
+			continue
+		}
+
+		last := similar[len(similar)-1]
+		patterns = append(patterns, RecurringPattern{
+			Description:   last.Description,
+			Currency:      key.currency,
+			TypicalAmount: typicalAmount,
+			Cadence:       medianDuration(intervals),
+			LastSeen:      last.Timestamp,
+		})
+	}
+
+	sort.Slice(patterns, func(i, j int) bool {
+		if patterns[i].Description != patterns[j].Description {
+			return patterns[i].Description < patterns[j].Description
+		}
+		return patterns[i].Currency < patterns[j].Currency
+	})
+	return patterns
+}
+
+// categorize returns the first category (in alphabetical order) whose keyword occurs in the description
+// (case-insensitively), or an empty string if there is no such category.
+func categorize(description string, categories []string, keywords map[string][]string) string {
+	description = strings.ToLower(description)
+	for _, category := range categories {
+		for _, keyword := range keywords[category] {
+			if keyword != "" && strings.Contains(description, strings.ToLower(keyword)) {
+				return category
+			}
+		}
+	}
+	return ""
+}
+
+// SpendingByKeyword returns total outcome (as positive amounts) of the transactions by category.
+// keywords maps category names to keywords: a transaction belongs to a category if its description
+// contains any of the category's keywords, ignoring case. A transaction matching several categories
+// is counted only in the first of them in alphabetical order; transactions matching none are ignored.
+// Income transactions are ignored too. Amounts are summed as is, so the transactions should be in the same currency.
+func SpendingByKeyword(ts []*Transaction, keywords map[string][]string) map[string]int {
+	categories := make([]string, 0, len(keywords))
+	for category := range keywords {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+
+	spending := make(map[string]int)
+	for _, transaction := range ts {
+		if transaction.Amount >= 0 {
+			continue
+		}
+		if category := categorize(transaction.Description, categories, keywords); category != "" {
+			spending[category] -= transaction.Amount
+		}
+	}
+	return spending
+}
+
+// BudgetStatus represents comparison of spending in a category to its budget. All amounts are positive outcomes.
+type BudgetStatus struct {
+	Category   string
+	Budget     int
+	Spent      int
+	Remaining  int // negative if the category is over budget
+	OverBudget bool
+}
+
+// newBudgetStatus returns status of the category's budget given the spent amount.
+func newBudgetStatus(category string, budget int, spent int) *BudgetStatus {
+	return &BudgetStatus{
+		Category:   category,
+		Budget:     budget,
+		Spent:      spent,
+		Remaining:  budget - spent,
+		OverBudget: spent > budget,
+	}
+}
+
+// minAnomalySampleSize is the minimum number of transactions in a currency needed to detect anomalies.
+const minAnomalySampleSize = 3
+
+// meanAndStdDev returns arithmetic mean and sample standard deviation of the values.
+func meanAndStdDev(values []float64) (mean float64, stdDev float64) {
+	for _, value := range values {
+		mean += value
+	}
+	mean /= float64(len(values))
+
+	if len(values) < 2 {
+		return mean, 0
+	}
+	var sumOfSquares float64
+	for _, value := range values {
+		sumOfSquares += (value - mean) * (value - mean)
+	}
+	return mean, math.Sqrt(sumOfSquares / float64(len(values)-1))
+}
+
+// DetectAnomalies returns transactions whose amounts differ from the mean amount of transactions
+// in the same currency by more than zThreshold (sample) standard deviations, in their original order.
+// Currencies with fewer than three transactions or with all amounts equal are skipped,
+// as there are not enough data to call anything unusual.
+func DetectAnomalies(ts []*Transaction, zThreshold float64) []*Transaction {
+	amountsByCurrency := make(map[string][]float64)
+	for _, transaction := range ts {
+		amountsByCurrency[transaction.Currency] = append(amountsByCurrency[transaction.Currency], float64(transaction.Amount))
+	}
+
+	type statistics struct {
+		mean   float64
+		stdDev float64
+	}
+	statisticsByCurrency := make(map[string]statistics, len(amountsByCurrency))
+	for currency, amounts := range amountsByCurrency {
+		if len(amounts) < minAnomalySampleSize {
+			continue
+		}
+		mean, stdDev := meanAndStdDev(amounts)
+		if stdDev == 0 {
+			continue
+		}
+		statisticsByCurrency[currency] = statistics{mean, stdDev}
+	}
+
+	anomalies := make([]*Transaction, 0)
+	for _, transaction := range ts {
+		stats, ok := statisticsByCurrency[transaction.Currency]
+		if !ok {
+			continue
+		}
+		if math.Abs(float64(transaction.Amount)-stats.mean)/stats.stdDev > zThreshold {
+			anomalies = append(anomalies, transaction)
+		}
+	}
+	return anomalies
+}
@@ -0,0 +1,68 @@
+package go_groshi
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectRecurringCadence(t *testing.T) {
+	start := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	ts := make([]*Transaction, 0)
+	for i, days := range []int{0, 30, 61, 91} {
+		ts = append(ts, &Transaction{
+			UUID:        string(rune('a' + i)),
+			Amount:      -999,
+			Currency:    "USD",
+			Description: "Netflix #12" + string(rune('0'+i)),
+			Timestamp:   start.AddDate(0, 0, days),
+		})
+	}
+
+	patterns := DetectRecurring(ts, 3)
+	require.Len(t, patterns, 1)
+	assert.Equal(t, 30*24*time.Hour, patterns[0].Cadence) // median of 30, 31 and 30 days
+	assert.Equal(t, -999, patterns[0].TypicalAmount)
+	assert.Equal(t, start.AddDate(0, 0, 91), patterns[0].LastSeen)
+}
+
+func TestDetectAnomalies(t *testing.T) {
+	transaction := func(uuid string, amount int, currency string) *Transaction {
+		return &Transaction{UUID: uuid, Amount: amount, Currency: currency}
+	}
+	// amounts 0, 0, 0 and 30 have mean 7.5 and sample standard deviation 15, so 30 is 1.5 deviations away
+	skewed := []*Transaction{
+		transaction("a", 0, "USD"), transaction("b", 0, "USD"), transaction("c", 0, "USD"), transaction("d", 30, "USD"),
+	}
+
+	tests := []struct {
+		name       string
+		ts         []*Transaction
+		zThreshold float64
+		want       []string
+	}{
+		{"no transactions", nil, 1, []string{}},
+		{"fewer than three samples", []*Transaction{
+			transaction("a", 100, "USD"), transaction("b", 100000, "USD"),
+		}, 0.1, []string{}},
+		{"zero standard deviation", []*Transaction{
+			transaction("a", 5, "USD"), transaction("b", 5, "USD"), transaction("c", 5, "USD"),
+		}, 0, []string{}},
+		{"below threshold", skewed, 1.4, []string{"d"}},
+		{"at threshold", skewed, 1.5, []string{}},
+		{"separated by currency", append([]*Transaction{
+			transaction("x", 30, "EUR"), transaction("y", 30, "EUR"), // too few EUR samples to judge
+		}, skewed...), 1, []string{"d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uuids := make([]string, 0)
+			for _, anomaly := range DetectAnomalies(tt.ts, tt.zThreshold) {
+				uuids = append(uuids, anomaly.UUID)
+			}
+			assert.Equal(t, tt.want, uuids)
+		})
+	}
+}
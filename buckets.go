@@ -0,0 +1,70 @@
+package go_groshi
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Bucket represents length of periods which transactions are aggregated by.
+type Bucket int
+
+const (
+	BucketDay   Bucket = iota // calendar day, starting at midnight
+	BucketWeek                // week, starting on Monday at midnight
+	BucketMonth               // calendar month, starting on the first day at midnight
+)
+
+func (b Bucket) String() string {
+	switch b {
+	case BucketDay:
+		return "day"
+	case BucketWeek:
+		return "week"
+	case BucketMonth:
+		return "month"
+	default:
+		return fmt.Sprintf("Bucket(%d)", int(b))
+	}
+}
+
+// start returns the start of the bucket containing t, with boundaries computed in loc.
+func (b Bucket) start(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	switch b {
+	case BucketWeek:
+		daysSinceMonday := (int(t.Weekday()) + 6) % 7
+		return time.Date(t.Year(), t.Month(), t.Day()-daysSinceMonday, 0, 0, 0, 0, loc)
+	case BucketMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, loc)
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, loc)
+	}
+}
+
+// next returns the start of the bucket following the one starting at start.
+func (b Bucket) next(start time.Time) time.Time {
+	switch b {
+	case BucketWeek:
+		return start.AddDate(0, 0, 7)
+	case BucketMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// bucketStarts returns starts of all buckets overlapping range [startTime, endTime].
+func (b Bucket) bucketStarts(startTime time.Time, endTime time.Time, loc *time.Location) []time.Time {
+	starts := make([]time.Time, 0)
+	for start := b.start(startTime, loc); !start.After(endTime); start = b.next(start) {
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+// bucketIndex returns index of the bucket containing t among buckets returned by bucketStarts,
+// or -1 if t is outside of them.
+func bucketIndex(starts []time.Time, t time.Time) int {
+	return sort.Search(len(starts), func(i int) bool { return starts[i].After(t) }) - 1
+}
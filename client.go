@@ -2,16 +2,41 @@ package go_groshi
 
 import (
 	"bytes"
+	"context"
+	"crypto/tls"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
+	"unicode"
 )
 
-const timeFormat = time.RFC3339 // RFC-3339 is the time format which is used by groshi API
+const timeFormat = time.RFC3339 // RFC-3339 is the default time format of timestamps sent to groshi API, see SetTimeFormat
+
+const defaultTimeout = 10 * time.Second // default timeout of requests, see SetTimeout
+
+const authRefreshPath = "/auth/refresh"
+
+const summaryPath = "/transactions/summary"
+
+// DefaultAPIVersion is the latest version of groshi API supported by the client, see SetAPIVersion.
+const DefaultAPIVersion = "1"
+
+// apiVersionHeader is the name of the HTTP header used to request a particular version of groshi API.
+const apiVersionHeader = "X-API-Version"
+
+// Version is the version of go-groshi, reported in the default User-Agent header.
+const Version = "0.1.0"
+
+const defaultUserAgent = "go-groshi/" + Version
 
 // APIError represents groshi API error.
 type APIError struct {
@@ -19,6 +44,73 @@ type APIError struct {
 
 	ErrorMessage string
 	ErrorDetails []string
+
+	RawBody string // beginning of the response body if it could not be parsed as groshi API error, empty otherwise
+
+	// FieldErrors are the details following the "field: message" convention, parsed for form display.
+	// Details not following it are available only in ErrorDetails, which always contains all details.
+	FieldErrors []FieldError
+
+	// RetryAfter is the wait duration requested by the server with the Retry-After header
+	// (usually with 429 Too Many Requests or 503 Service Unavailable), zero if there was no such header.
+	RetryAfter time.Duration
+}
+
+// FieldError is a validation error of a particular field of the request, such as "amount" or "currency".
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+// parseFieldError parses error detail following the "field: message" convention,
+// where field consists of letters, digits, underscores and dots (for nested fields).
+func parseFieldError(detail string) (FieldError, bool) {
+	field, message, ok := strings.Cut(detail, ":")
+	if !ok || field == "" {
+		return FieldError{}, false
+	}
+	for _, r := range field {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' {
+			return FieldError{}, false
+		}
+	}
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return FieldError{}, false
+	}
+	return FieldError{Field: field, Message: message}, true
+}
+
+// Sentinel errors matching APIError with the respective HTTP status code via errors.Is, for example:
+//
+// if errors.Is(err, ErrNotFound) { ... }
+//
+// Details of the error remain accessible with errors.As(err, &apiErr).
+var (
+	ErrUnauthorized = errors.New("unauthorized")      // 401 Unauthorized
+	ErrNotFound     = errors.New("not found")         // 404 Not Found
+	ErrConflict     = errors.New("conflict")          // 409 Conflict
+	ErrValidation   = errors.New("validation failed") // 422 Unprocessable Entity
+
+	ErrUnsupportedAPIVersion = errors.New("unsupported API version") // 406 Not Acceptable, see SetAPIVersion
+)
+
+// Is reports whether the error matches target, which is one of the sentinel errors for HTTP statuses.
+func (e APIError) Is(target error) bool {
+	switch target {
+	case ErrUnauthorized:
+		return e.HTTPStatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return e.HTTPStatusCode == http.StatusNotFound
+	case ErrConflict:
+		return e.HTTPStatusCode == http.StatusConflict
+	case ErrValidation:
+		return e.HTTPStatusCode == http.StatusUnprocessableEntity
+	case ErrUnsupportedAPIVersion:
+		return e.HTTPStatusCode == http.StatusNotAcceptable
+	default:
+		return false
+	}
 }
 
 func (e APIError) Error() string {
@@ -29,79 +121,426 @@ func (e APIError) Error() string {
 	}
 }
 
+// TransportError represents failure to exchange HTTP request and response with groshi API
+// (e.g. DNS failure, refused connection or timeout), as opposed to APIError returned by the server.
+// The underlying error remains accessible with errors.Is and errors.As.
+type TransportError struct {
+	Err error
+}
+
+func (e TransportError) Error() string {
+	return fmt.Sprintf("transport error: %v", e.Err)
+}
+
+func (e TransportError) Unwrap() error {
+	return e.Err
+}
+
+// IsTimeout reports whether the failure was caused by a timeout,
+// including the one set by SetTimeout and deadlines of contexts passed to the methods.
+func (e TransportError) IsTimeout() bool {
+	var netErr net.Error
+	if errors.As(e.Err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return errors.Is(e.Err, context.DeadlineExceeded)
+}
+
 // APIClient represents groshi API client and includes all groshi API methods.
+// APIClient is safe for concurrent use by multiple goroutines, including changing the token with SetToken
+// (or automatically, see SetAutoRefresh). Other setters configure the client and should be called
+// before it is shared between goroutines.
 type APIClient struct {
 	baseURL string
-	token   string
+
+	tokenMutex     sync.RWMutex
+	token          string
+	tokenExpiresAt time.Time
+
+	httpClient *http.Client
+	transport  *http.Transport // default transport of httpClient, configured by SetMinTLSVersion
+	timeout    time.Duration
+
+	retryConfig    RetryConfig
+	backoff        Backoff
+	retryPredicate RetryPredicate
+
+	strict           bool
+	maxTimestampSkew time.Duration
+
+	maxResults int
+
+	readOnly bool
+
+	currenciesMutex sync.Mutex
+	currencies      []*Currency
+
+	serverDecimalPlaces sync.Map // map[string]int, see registerDecimalPlaces
+
+	idempotencyHeader string
+
+	autoRefresh bool
+
+	roundingMode RoundingMode
+
+	contextHeaderExtractor func(ctx context.Context) map[string]string
+
+	limitsMutex       sync.Mutex
+	limits            *Limits
+	descriptionPolicy DescriptionPolicy
+
+	concurrency int
+
+	responseInspector func(response *http.Response)
+
+	userAgent string
+
+	logger Logger
+
+	baseCtx context.Context
+
+	timeFormat string
+
+	defaultCurrency string
+
+	closed atomic.Bool
+
+	maxResponseBytes int64
+
+	compressRequests bool
+
+	autoRefreshMutex  sync.Mutex
+	autoRefreshCancel context.CancelFunc
+	autoRefreshDone   chan struct{}
+
+	location *time.Location
+
+	validatorsMutex sync.Mutex
+	validators      map[string]cacheValidators
+
+	apiVersion string
+
+	destructiveOperations bool
+
+	operationTimeouts map[string]time.Duration
+
+	now func() time.Time // returns the current time, see currentTime
+}
+
+// ErrNoToken is returned by methods requiring authorization if the client has no token.
+// Use SetToken or Auth to authorize the client.
+var ErrNoToken = errors.New("authorization token is not set")
+
+// ResponseMeta contains metadata of a successful groshi API response.
+type ResponseMeta struct {
+	StatusCode int // e.g. 200 or 201
+	Header     http.Header
 }
 
 // sendRequest is the basic method for sending HTTP requests to groshi API.
 func (c *APIClient) sendRequest(
-	method string, path string, queryParams map[string]string, bodyParams map[string]any, authorize bool, v interface{},
+	ctx context.Context, method string, path string, queryParams url.Values, requestBody any, authorize bool, v interface{},
 ) error {
-	if authorize && c.token == "" {
-		panic("`authorize` is set to true, but APIClient's field `token` is an empty string")
+	_, err := c.sendRequestMeta(ctx, method, path, queryParams, requestBody, authorize, v)
+	return err
+}
+
+// sendRequestMeta is like sendRequest but also returns metadata of the successful response.
+// If automatic token refresh is enabled, an authorized request failed with 401 Unauthorized
+// is sent once again after refreshing the token.
+func (c *APIClient) sendRequestMeta(
+	ctx context.Context, method string, path string, queryParams url.Values, requestBody any, authorize bool, v interface{},
+) (meta *ResponseMeta, err error) {
+	statusCode := 0 // stays zero if no response is received
+	if c.logger != nil {
+		startTime := time.Now()
+		defer func() {
+			loggedURL := c.baseURL + path // in case the URL cannot be built
+			if urlObject, urlErr := c.requestURL(path, queryParams); urlErr == nil {
+				loggedURL = urlObject.String()
+			}
+			c.logger(method, loggedURL, statusCode, time.Since(startTime), err)
+		}()
+	}
+
+	if c.closed.Load() {
+		return nil, ErrClosed
 	}
 
-	// create URL object and set query params:
-	urlObject, err := url.Parse(c.baseURL + path)
+	ctx, cancel, err := c.withBaseContext(ctx)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	defer cancel()
+
+	meta, err = c.sendRequestOnce(ctx, method, path, queryParams, requestBody, authorize, v, &statusCode)
+
+	if !authorize || !c.autoRefresh || path == authRefreshPath || autoRefreshDisabled(ctx) || !errors.Is(err, ErrUnauthorized) {
+		return meta, err
+	}
+
+	authorization, refreshErr := c.AuthRefreshContext(ctx)
+	if refreshErr != nil {
+		return nil, fmt.Errorf("%w (%v): %w", ErrTokenRefreshFailed, refreshErr, err)
 	}
+	c.SetAuthorization(authorization)
+
+	return c.sendRequestOnce(ctx, method, path, queryParams, requestBody, authorize, v, &statusCode)
+}
+
+// ErrClosed is returned by all methods sending requests after the client was closed with Close.
+var ErrClosed = errors.New("client is closed")
+
+// Close closes idle connections of the client's HTTP client (see http.Client.CloseIdleConnections),
+// after which all methods sending requests return ErrClosed. Requests in progress are not interrupted,
+// while the background token refresh (see StartAutoRefresh) is stopped.
+// It is safe to call Close multiple times. Note that if an HTTP client shared with other code
+// was set with SetHTTPClient, idle connections of its transport are closed for that code too.
+func (c *APIClient) Close() error {
+	if c.closed.Swap(true) {
+		return nil
+	}
+	c.StopAutoRefresh()
+	c.httpClient.CloseIdleConnections()
+	// the client's own transport may be wrapped (see WithTransport) by RoundTrippers unable to close connections:
+	c.transport.CloseIdleConnections()
+	return nil
+}
+
+// withBaseContext returns a copy of ctx which is also cancelled when the client's base context (see WithContext) is done.
+// If the base context is already done, its error is returned and no request should be sent.
+func (c *APIClient) withBaseContext(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	if c.baseCtx == nil {
+		return ctx, func() {}, nil
+	}
+	if err := c.baseCtx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := context.WithCancelCause(ctx)
+	stop := context.AfterFunc(c.baseCtx, func() {
+		cancel(c.baseCtx.Err())
+	})
+	return ctx, func() {
+		stop()
+		cancel(nil)
+	}, nil
+}
+
+// requestURL returns URL of the request to groshi API with the given path and query params.
+// The path is joined to the path of the base URL, so that prefixes like "/groshi/api" are preserved.
+func (c *APIClient) requestURL(path string, queryParams url.Values) (*url.URL, error) {
+	baseURL, err := url.Parse(c.baseURL)
+	if err != nil {
+		return nil, err
+	}
+	urlObject := baseURL.JoinPath(path)
 
 	queryParamsObject := urlObject.Query()
-	for key, value := range queryParams {
-		queryParamsObject.Add(key, value)
+	for key, values := range queryParams {
+		for _, value := range values {
+			queryParamsObject.Add(key, value)
+		}
 	}
 	urlObject.RawQuery = queryParamsObject.Encode()
+	return urlObject, nil
+}
 
-	// encode request body:
-	body, err := json.Marshal(bodyParams)
-	if err != nil {
-		return err
+// sendRequestOnce sends a single HTTP request (possibly retried according to RetryConfig) to groshi API.
+// The status code of the response is stored in statusCode as soon as it is received.
+func (c *APIClient) sendRequestOnce(
+	ctx context.Context, method string, path string, queryParams url.Values, requestBody any, authorize bool, v interface{},
+	statusCode *int,
+) (*ResponseMeta, error) {
+	token := c.Token()
+	if authorize && token == "" {
+		return nil, ErrNoToken
 	}
 
-	request, err := http.NewRequest(method, urlObject.String(), bytes.NewBuffer(body))
+	urlObject, err := c.requestURL(path, queryParams)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	request.Header.Set("Content-Type", "application/json")
-	if authorize {
-		request.Header.Set("Authorization", fmt.Sprintf("Bearer %v", c.token))
+	// encode request body (requests without body have no body at all):
+	var body []byte
+	if requestBody != nil {
+		if body, err = json.Marshal(requestBody); err != nil {
+			return nil, err
+		}
 	}
+	compressed := c.compressRequests && body != nil
+	if compressed {
+		if body, err = gzipBody(body); err != nil {
+			return nil, err
+		}
+	}
+
+	if timeout := c.timeoutFor(path); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	newRequest := func() (*http.Request, error) {
+		var bodyReader io.Reader
+		if body != nil {
+			bodyReader = bytes.NewReader(body)
+		}
+		request, err := http.NewRequestWithContext(ctx, method, urlObject.String(), bodyReader)
+		if err != nil {
+			return nil, err
+		}
 
-	httpClient := http.Client{
-		Timeout: 10 * time.Second,
+		if c.contextHeaderExtractor != nil {
+			for key, value := range c.contextHeaderExtractor(ctx) {
+				request.Header.Set(key, value)
+			}
+		}
+		if body != nil {
+			request.Header.Set("Content-Type", "application/json; charset=utf-8")
+		}
+		if compressed {
+			request.Header.Set("Content-Encoding", "gzip")
+		}
+		request.Header.Set("User-Agent", c.userAgent)
+		request.Header.Set("Accept-Encoding", acceptEncoding)
+		if c.apiVersion != "" {
+			request.Header.Set(apiVersionHeader, c.apiVersion)
+		}
+		if authorize {
+			request.Header.Set("Authorization", fmt.Sprintf("Bearer %v", token))
+		}
+		for key, values := range requestHeadersFromContext(ctx) {
+			request.Header[key] = values
+		}
+		if idempotencyKey, ok := idempotencyKeyFromContext(ctx); ok {
+			request.Header.Set(c.idempotencyHeader, idempotencyKey)
+		}
+		return request, nil
 	}
 
-	httpResponse, err := httpClient.Do(request)
+	httpResponse, err := c.doWithRetries(ctx, c.httpClient, newRequest)
 	if err != nil {
-		return err
+		return nil, err
 	}
+	defer httpResponse.Body.Close()
+	*statusCode = httpResponse.StatusCode
 
-	responseBody, err := io.ReadAll(httpResponse.Body)
+	responseBody, err := c.readResponseBody(httpResponse)
 	if err != nil {
-		return err
+		if errors.Is(err, ErrResponseTooLarge) {
+			return nil, err
+		}
+		return nil, TransportError{Err: err}
+	}
+	if c.responseInspector != nil {
+		// the inspector gets its own copy of the body, so that it can read it freely:
+		httpResponse.Body = io.NopCloser(bytes.NewReader(responseBody))
+		c.responseInspector(httpResponse)
 	}
 
-	if httpResponse.StatusCode == http.StatusOK {
-		if err := json.Unmarshal(responseBody, &v); err != nil {
-			return err
+	if httpResponse.StatusCode == http.StatusNotModified {
+		// only possible for conditional requests (see requestHeadersFromContext), v is left unchanged:
+		return &ResponseMeta{
+			StatusCode: httpResponse.StatusCode,
+			Header:     httpResponse.Header,
+		}, nil
+	}
+	if httpResponse.StatusCode >= 200 && httpResponse.StatusCode < 300 {
+		// responses without body (e.g. 204 No Content) are successful too, v is left zero-valued:
+		if len(bytes.TrimSpace(responseBody)) > 0 {
+			if err := json.Unmarshal(responseBody, &v); err != nil {
+				return nil, err
+			}
+			c.convertTimes(v)
 		}
-		return nil
+		return &ResponseMeta{
+			StatusCode: httpResponse.StatusCode,
+			Header:     httpResponse.Header,
+		}, nil
 	} else {
-		errorModel := Error{}
-		if err := json.Unmarshal(responseBody, &errorModel); err != nil {
-			return err
+		apiErr := parseAPIError(httpResponse.StatusCode, responseBody)
+		apiErr.RetryAfter, _ = parseRetryAfter(httpResponse.Header.Get("Retry-After"), time.Now())
+		return nil, apiErr
+	}
+}
+
+// defaultMaxResponseBytes is the default limit of the size of response bodies, see SetMaxResponseBytes.
+const defaultMaxResponseBytes = 10 << 20 // 10 MiB
+
+// ErrResponseTooLarge is returned when a response body exceeds the limit set by SetMaxResponseBytes.
+var ErrResponseTooLarge = errors.New("response body is too large")
+
+// SetMaxResponseBytes limits the size of response bodies read by the client, so that a misbehaving server
+// cannot exhaust memory. Reading a larger body fails with ErrResponseTooLarge.
+// The default limit is 10 MiB; zero or a negative value disables the limit.
+func (c *APIClient) SetMaxResponseBytes(n int64) {
+	c.maxResponseBytes = n
+}
+
+// readResponseBody reads the whole (decompressed, see decodeResponseBody) response body,
+// enforcing the limit set by SetMaxResponseBytes.
+func (c *APIClient) readResponseBody(response *http.Response) ([]byte, error) {
+	body, err := decodeResponseBody(response)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	if c.maxResponseBytes <= 0 {
+		return io.ReadAll(body)
+	}
+
+	// read one byte more than allowed to detect overflow:
+	data, err := io.ReadAll(io.LimitReader(body, c.maxResponseBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > c.maxResponseBytes {
+		return nil, fmt.Errorf("%w: more than %v bytes", ErrResponseTooLarge, c.maxResponseBytes)
+	}
+	return data, nil
+}
+
+// maxRawBodyInError is the maximum number of bytes of unparsable error response body included in APIError.
+const maxRawBodyInError = 512
+
+// parseAPIError converts error response into APIError. It never fails: if the body is not a valid
+// error model, the error message is derived from the status code and the raw body is attached to the error.
+// Error details which are not strings are included as their JSON representation.
+func parseAPIError(statusCode int, body []byte) APIError {
+	apiErr := APIError{HTTPStatusCode: statusCode}
+
+	var errorModel struct {
+		ErrorMessage *string           `json:"error_message"`
+		ErrorDetails []json.RawMessage `json:"error_details"`
+	}
+	if err := json.Unmarshal(body, &errorModel); err != nil || errorModel.ErrorMessage == nil {
+		apiErr.ErrorMessage = fmt.Sprintf("unexpected error response with status %v %v", statusCode, http.StatusText(statusCode))
+		rawBody := body
+		if len(rawBody) > maxRawBodyInError {
+			rawBody = rawBody[:maxRawBodyInError]
+		}
+		apiErr.RawBody = string(rawBody)
+		if len(rawBody) > 0 {
+			apiErr.ErrorDetails = []string{fmt.Sprintf("response body: %q", rawBody)}
 		}
-		return APIError{
-			ErrorMessage: errorModel.ErrorMessage,
-			ErrorDetails: errorModel.ErrorDetails,
+		return apiErr
+	}
 
-			HTTPStatusCode: httpResponse.StatusCode,
+	apiErr.ErrorMessage = *errorModel.ErrorMessage
+	for _, rawDetail := range errorModel.ErrorDetails {
+		var detail string
+		if err := json.Unmarshal(rawDetail, &detail); err != nil {
+			detail = string(rawDetail)
+		}
+		apiErr.ErrorDetails = append(apiErr.ErrorDetails, detail)
+		if fieldError, ok := parseFieldError(detail); ok {
+			apiErr.FieldErrors = append(apiErr.FieldErrors, fieldError)
 		}
 	}
+	return apiErr
 }
 
 // SetToken is a setter method for authorization token.
@@ -109,44 +548,237 @@ func (c *APIClient) sendRequest(
 // to create a new user and then perform some operations
 // that require authorization. For example:
 //
-// client := NewAPIClient("http://localhost:8080", "") // create groshi client with empty token
+// client, _ := NewClient("http://localhost:8080") // create groshi client without token
 // _, _ = client.UserCreate("username-1234", "password-1234")
 // auth, _ := client.AuthLogin("username-1234", "password-1234")
-// client.SetToken(auth.Token)
+// client.SetToken(auth.Token) // or client.SetAuthorization(auth) to keep the expiry
 // currentUser, _ := client.UserRead()
 // fmt.Printf("Authorized as %v", currentUser.Username)
+//
+// The expiry of a token set this way is unknown, so IsTokenExpired reports it as expired.
 func (c *APIClient) SetToken(token string) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
 	c.token = token
+	c.tokenExpiresAt = time.Time{}
+}
+
+// SetAuthorization sets the token received from AuthLogin or AuthRefresh along with its expiry,
+// see TokenExpiresAt. Auth and automatic token refresh set the authorization this way.
+func (c *APIClient) SetAuthorization(authorization *Authorization) {
+	c.tokenMutex.Lock()
+	defer c.tokenMutex.Unlock()
+	c.token = authorization.Token
+	c.tokenExpiresAt = authorization.ExpiresAt
+}
+
+// ErrResultSetTooLarge is returned by methods reading many transactions
+// when the number of transactions exceeds the limit set by SetMaxResults.
+var ErrResultSetTooLarge = errors.New("result set is too large")
+
+// SetMaxResults limits the number of transactions TransactionsReadMany may return.
+// When the limit is exceeded, ErrResultSetTooLarge is returned instead of the transactions.
+// The limit is also sent to the server as the `limit` query param (set to n+1 to detect overflow),
+// so that a server supporting it never sends more than necessary.
+// Zero (the default) disables the limit.
+func (c *APIClient) SetMaxResults(n int) {
+	c.maxResults = n
+}
+
+// checkResultsCount returns ErrResultSetTooLarge if count exceeds the limit set by SetMaxResults.
+func (c *APIClient) checkResultsCount(count int) error {
+	if c.maxResults > 0 && count > c.maxResults {
+		return fmt.Errorf("%w: more than %v transactions", ErrResultSetTooLarge, c.maxResults)
+	}
+	return nil
+}
+
+// ErrReadOnly is returned by methods modifying data when the client is in read-only mode.
+var ErrReadOnly = errors.New("client is in read-only mode")
+
+// SetReadOnly enables or disables read-only mode. In read-only mode, methods creating,
+// updating or deleting users and transactions return ErrReadOnly without sending any request,
+// while reading methods work as usual. It is useful for embedding the client in reporting contexts.
+func (c *APIClient) SetReadOnly(readOnly bool) {
+	c.readOnly = readOnly
+}
+
+// checkWritable returns ErrReadOnly if the client is in read-only mode.
+func (c *APIClient) checkWritable() error {
+	if c.readOnly {
+		return ErrReadOnly
+	}
+	return nil
+}
+
+// SetHTTPClient sets the HTTP client used to send requests, e.g. to configure proxies or share a transport.
+// Options set by SetMinTLSVersion do not apply to it, while the timeout set by SetTimeout still does.
+// By default, the client owns an HTTP client created once and reused for all requests.
+func (c *APIClient) SetHTTPClient(httpClient *http.Client) {
+	c.httpClient = httpClient
+}
+
+// SetTimeout sets the time limit for every request, including retries and reading the response body.
+// Zero means no timeout, so that requests are limited only by their contexts. The default is 10 seconds.
+// The timeout is applied on top of the context passed to the method (and of the Timeout of
+// an HTTP client set with SetHTTPClient), so the shortest of them wins.
+// Timeouts of particular categories of operations can be overridden with SetOperationTimeout.
+func (c *APIClient) SetTimeout(timeout time.Duration) {
+	c.timeout = timeout
+}
+
+// SetMinTLSVersion sets the minimum TLS version accepted when connecting to groshi API over HTTPS,
+// for example tls.VersionTLS13. The default minimum version is TLS 1.2.
+// It has no effect on clients set with SetHTTPClient.
+func (c *APIClient) SetMinTLSVersion(v uint16) {
+	c.transport.TLSClientConfig.MinVersion = v
+}
+
+// SetAPIVersion pins the client to the given version of groshi API, sent with every request in the X-API-Version header.
+// The server is expected to serve the requested version and to respond with 406 Not Acceptable if it does not
+// support it, which is matched by ErrUnsupportedAPIVersion via errors.Is. Servers without versioning ignore the header.
+// The default is DefaultAPIVersion; empty string disables the header.
+func (c *APIClient) SetAPIVersion(v string) {
+	c.apiVersion = v
+}
+
+// SetResponseInspector sets a function called with every HTTP response received from groshi API
+// (including error responses, but not failed retry attempts), e.g. to read rate limit headers.
+// The inspector may read the response body: decoding does not depend on it.
+func (c *APIClient) SetResponseInspector(inspector func(response *http.Response)) {
+	c.responseInspector = inspector
+}
+
+// SetContextHeaderExtractor sets a function extracting additional request headers
+// (such as tenant, request ID or locale) from the context of every request,
+// which propagates request-scoped metadata without changing method signatures.
+// The function is called for every HTTP request, including retries, with the context passed to the method.
+// Extracted headers do not override headers set by the client itself (e.g. Authorization).
+func (c *APIClient) SetContextHeaderExtractor(extractor func(ctx context.Context) map[string]string) {
+	c.contextHeaderExtractor = extractor
+}
+
+// Token returns the current authorization token,
+// which may differ from the one originally set if it was refreshed automatically (see SetAutoRefresh).
+func (c *APIClient) Token() string {
+	c.tokenMutex.RLock()
+	defer c.tokenMutex.RUnlock()
+	return c.token
+}
+
+// TokenExpiresAt returns the expiry of the current token,
+// or zero time if it is unknown (e.g. the token was set with SetToken) or there is no token.
+// It can be used to refresh the token proactively, e.g. a minute before it expires.
+func (c *APIClient) TokenExpiresAt() time.Time {
+	c.tokenMutex.RLock()
+	defer c.tokenMutex.RUnlock()
+	return c.tokenExpiresAt
+}
+
+// IsTokenExpired reports whether the current token has expired according to TokenExpiresAt.
+// A missing token or a token with unknown expiry is reported as expired.
+func (c *APIClient) IsTokenExpired() bool {
+	expiresAt := c.TokenExpiresAt()
+	return expiresAt.IsZero() || !time.Now().Before(expiresAt)
+}
+
+// ErrTokenRefreshFailed is returned when a request failed with 401 Unauthorized and automatic token refresh failed too.
+// The returned error also wraps the original APIError, which can be extracted with errors.As.
+var ErrTokenRefreshFailed = errors.New("token refresh failed")
+
+// SetAutoRefresh enables or disables automatic token refresh. When enabled, an authorized request
+// failed with 401 Unauthorized makes the client call AuthRefresh, store the new token (see Token)
+// and send the original request once again. If the refresh fails, the returned error wraps both
+// ErrTokenRefreshFailed and the original APIError. Automatic refresh is disabled by default.
+func (c *APIClient) SetAutoRefresh(autoRefresh bool) {
+	c.autoRefresh = autoRefresh
+}
+
+type noAutoRefreshContextKey struct{}
+
+// autoRefreshDisabled reports whether automatic token refresh is disabled for requests with the context.
+func autoRefreshDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(noAutoRefreshContextKey{}).(bool)
+	return disabled
+}
+
+// ValidateToken checks whether the current token is accepted by the server, using the lightweight UserRead method.
+// It returns true if the token is valid, false if the server responded with 401 Unauthorized
+// (or if there is no token at all), and an error if validity could not be determined.
+// It does not change the client state: the token is never refreshed automatically by this method.
+func (c *APIClient) ValidateToken() (bool, error) {
+	if c.Token() == "" {
+		return false, nil
+	}
+
+	ctx := context.WithValue(context.Background(), noAutoRefreshContextKey{}, true)
+	user := User{}
+	err := c.sendRequest(
+		ctx,
+		http.MethodGet,
+		"/user",
+		nil,
+		nil,
+		true,
+		&user,
+	)
+	if err != nil {
+		if errors.Is(err, ErrUnauthorized) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
 }
 
 // Auth is a helper function that uses AuthLogin groshi API method to authorize user.
 // It also sets Token field of the `c` to the received token. Example:
 //
-// client := NewAPIClient("http://localhost:8080", "")
+// client, _ := NewClient("http://localhost:8080")
 // err := client.Auth("username-1234", "password-1234")
 // currentUser, _ := client.UserRead()
 // fmt.Printf("Authorized as %v", currentUser.Username)
 func (c *APIClient) Auth(username string, password string) error {
-	authorization, err := c.AuthLogin(username, password)
+	return c.AuthContext(context.Background(), username, password)
+}
+
+// ErrInvalidCredentials is returned by AuthContext when the server rejects the username or password.
+var ErrInvalidCredentials = errors.New("invalid credentials")
+
+// AuthContext is like Auth but uses the given context for the request.
+// Since logging in has no side effects, the request is retried according to SetRetryConfig
+// even though it is a POST request. If the server rejects the credentials with 401 Unauthorized
+// or 403 Forbidden, the returned error wraps both ErrInvalidCredentials and the APIError;
+// other errors (e.g. network errors after all retries) are returned as is.
+func (c *APIClient) AuthContext(ctx context.Context, username string, password string) error {
+	authorization, err := c.AuthLoginContext(withSafeToRetry(ctx), username, password)
 	if err != nil {
+		var apiErr APIError
+		if errors.As(err, &apiErr) &&
+			(apiErr.HTTPStatusCode == http.StatusUnauthorized || apiErr.HTTPStatusCode == http.StatusForbidden) {
+			return fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+		}
 		return err
 	}
-	c.SetToken(authorization.Token)
+	c.SetAuthorization(authorization)
 	return nil
 }
 
 // methods related to authorization:
 
 func (c *APIClient) AuthLogin(username string, password string) (*Authorization, error) {
+	return c.AuthLoginContext(context.Background(), username, password)
+}
+
+// AuthLoginContext is like AuthLogin but uses the given context for the request.
+func (c *APIClient) AuthLoginContext(ctx context.Context, username string, password string) (*Authorization, error) {
 	authorization := Authorization{}
 	err := c.sendRequest(
+		ctx,
 		http.MethodPost,
 		"/auth/login",
 		nil,
-		map[string]any{
-			"username": username,
-			"password": password,
-		},
+		credentialsRequest{Username: username, Password: password},
 		false,
 		&authorization,
 	)
@@ -157,10 +789,16 @@ func (c *APIClient) AuthLogin(username string, password string) (*Authorization,
 }
 
 func (c *APIClient) AuthRefresh() (*Authorization, error) {
+	return c.AuthRefreshContext(context.Background())
+}
+
+// AuthRefreshContext is like AuthRefresh but uses the given context for the request.
+func (c *APIClient) AuthRefreshContext(ctx context.Context) (*Authorization, error) {
 	authorization := Authorization{}
 	err := c.sendRequest(
+		ctx,
 		http.MethodPost,
-		"/auth/refresh",
+		authRefreshPath,
 		nil,
 		nil,
 		true,
@@ -175,15 +813,17 @@ func (c *APIClient) AuthRefresh() (*Authorization, error) {
 // methods related to user:
 
 func (c *APIClient) UserCreate(username string, password string) (*User, error) {
+	if err := c.checkWritable(); err != nil {
+		return nil, err
+	}
+
 	user := User{}
 	err := c.sendRequest(
+		context.Background(),
 		http.MethodPost,
 		"/user",
 		nil,
-		map[string]any{
-			"username": username,
-			"password": password,
-		},
+		credentialsRequest{Username: username, Password: password},
 		false,
 		&user,
 	)
@@ -196,6 +836,7 @@ func (c *APIClient) UserCreate(username string, password string) (*User, error)
 func (c *APIClient) UserRead() (*User, error) {
 	user := User{}
 	err := c.sendRequest(
+		context.Background(),
 		http.MethodGet,
 		"/user",
 		nil,
@@ -210,32 +851,97 @@ func (c *APIClient) UserRead() (*User, error) {
 }
 
 func (c *APIClient) UserUpdate(newUsername *string, newPassword *string) (*User, error) {
-	bodyParams := make(map[string]any)
-	if newUsername != nil {
-		bodyParams["new_username"] = *newUsername
+	return c.UserUpdateWithCurrentPassword(nil, newUsername, newPassword)
+}
+
+// ErrWrongPassword is returned by UserUpdateWithCurrentPassword when the server rejects the current password.
+var ErrWrongPassword = errors.New("current password is wrong")
+
+// UserUpdateWithCurrentPassword is like UserUpdate but also sends the current password (if it is not nil)
+// for servers requiring confirmation of sensitive changes. If the server rejects the request
+// with 401 Unauthorized or 403 Forbidden, the returned error wraps both ErrWrongPassword and the APIError.
+func (c *APIClient) UserUpdateWithCurrentPassword(
+	currentPassword *string, newUsername *string, newPassword *string,
+) (*User, error) {
+	if err := c.checkWritable(); err != nil {
+		return nil, err
 	}
-	if newPassword != nil {
-		bodyParams["new_password"] = *newPassword
+
+	requestBody := userUpdateRequest{
+		CurrentPassword: currentPassword,
+		NewUsername:     newUsername,
+		NewPassword:     newPassword,
 	}
 
 	user := User{}
 	err := c.sendRequest(
+		context.Background(),
 		http.MethodPut,
 		"/user",
 		nil,
-		bodyParams,
+		requestBody,
 		true,
 		&user,
 	)
 	if err != nil {
+		var apiErr APIError
+		if currentPassword != nil && errors.As(err, &apiErr) &&
+			(apiErr.HTTPStatusCode == http.StatusUnauthorized || apiErr.HTTPStatusCode == http.StatusForbidden) {
+			return nil, fmt.Errorf("%w: %w", ErrWrongPassword, err)
+		}
 		return nil, err
 	}
 	return &user, nil
 }
 
+// ErrUsernameTaken is returned by RenameUser when another user already has the new username.
+var ErrUsernameTaken = errors.New("username is already taken")
+
+// RenameUser changes username of the current user, leaving the password unchanged.
+// If the server responds with 409 Conflict, the returned error wraps both ErrUsernameTaken and the APIError.
+func (c *APIClient) RenameUser(newUsername string) (*User, error) {
+	user, err := c.UserUpdate(&newUsername, nil)
+	if err != nil {
+		if errors.Is(err, ErrConflict) {
+			return nil, fmt.Errorf("%w: %w", ErrUsernameTaken, err)
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// ErrConfirmationRequired is returned by UserDelete unless destructive operations are enabled, see SetDestructiveOperations.
+var ErrConfirmationRequired = errors.New("destructive operation requires confirmation")
+
+// SetDestructiveOperations enables or disables destructive operations, i.e. UserDelete, which permanently deletes
+// the account. While they are disabled (the default), UserDelete returns ErrConfirmationRequired without sending
+// any request, and UserDeleteConfirmed must be used instead. It is a client-side safeguard against deleting
+// the account by mistake, the server does not ask for any confirmation.
+func (c *APIClient) SetDestructiveOperations(enabled bool) {
+	c.destructiveOperations = enabled
+}
+
+// UserDelete permanently deletes the current user. It returns ErrConfirmationRequired
+// unless destructive operations are enabled, see SetDestructiveOperations.
 func (c *APIClient) UserDelete() (*User, error) {
+	if err := c.checkWritable(); err != nil {
+		return nil, err
+	}
+	if !c.destructiveOperations {
+		return nil, ErrConfirmationRequired
+	}
+	return c.UserDeleteConfirmed()
+}
+
+// UserDeleteConfirmed is like UserDelete but deletes the current user even if destructive operations are disabled.
+func (c *APIClient) UserDeleteConfirmed() (*User, error) {
+	if err := c.checkWritable(); err != nil {
+		return nil, err
+	}
+
 	user := User{}
 	err := c.sendRequest(
+		context.Background(),
 		http.MethodDelete,
 		"/user",
 		nil,
@@ -252,43 +958,119 @@ func (c *APIClient) UserDelete() (*User, error) {
 // methods related to transactions:
 
 func (c *APIClient) TransactionsCreate(amount int, currency string, description *string, timestamp *time.Time) (*Transaction, error) {
-	bodyParams := map[string]any{
-		"amount":   amount,
-		"currency": currency,
+	return c.TransactionsCreateContext(context.Background(), amount, currency, description, timestamp)
+}
+
+// TransactionsCreateContext is like TransactionsCreate but uses the given context for the request.
+func (c *APIClient) TransactionsCreateContext(
+	ctx context.Context, amount int, currency string, description *string, timestamp *time.Time,
+) (*Transaction, error) {
+	transaction, _, err := c.TransactionsCreateWithMeta(ctx, amount, currency, description, timestamp)
+	return transaction, err
+}
+
+// TransactionsCreateWithMeta is like TransactionsCreateContext but also returns metadata of the response,
+// such as its status code, so that, for example, 200 OK can be distinguished from 201 Created.
+func (c *APIClient) TransactionsCreateWithMeta(
+	ctx context.Context, amount int, currency string, description *string, timestamp *time.Time,
+) (*Transaction, ResponseMeta, error) {
+	if err := c.checkWritable(); err != nil {
+		return nil, ResponseMeta{}, err
+	}
+	if err := c.validateTimestampStrict(timestamp); err != nil {
+		return nil, ResponseMeta{}, err
 	}
-	if description != nil {
-		bodyParams["description"] = *description
+	if _, ok := idempotencyKeyFromContext(ctx); !ok && c.retryConfig.enabled() {
+		ctx = WithIdempotencyKey(ctx, newIdempotencyKey()) // shared by all attempts, see WithIdempotencyKey
 	}
-	if timestamp != nil {
-		bodyParams["timestamp"] = *timestamp
+	if _, ok := idempotencyKeyFromContext(ctx); ok {
+		ctx = withSafeToRetry(ctx) // the key lets the server deduplicate the attempts
 	}
 
+	requestBody := c.newTransactionCreateRequest(amount, currency, description, timestamp)
+
 	transaction := Transaction{}
-	err := c.sendRequest(
+	meta, err := c.sendRequestMeta(
+		ctx,
 		http.MethodPost,
 		"/transactions",
 		nil,
-		bodyParams,
+		requestBody,
 		true,
 		&transaction,
 	)
+	if err != nil {
+		return nil, ResponseMeta{}, err
+	}
+	return &transaction, *meta, nil
+}
+
+// TransactionsCreateDecimal is like TransactionsCreate but takes amount as a decimal number in major units,
+// e.g. "12.34" for 12 dollars 34 cents, and converts it to minor units according to the currency's decimal places
+// (see CurrencyDecimalPlaces). Amounts with more fractional digits than the currency allows are rejected.
+func (c *APIClient) TransactionsCreateDecimal(
+	ctx context.Context, amount string, currency string, description *string, timestamp *time.Time,
+) (*Transaction, error) {
+	currency = c.currencyOrDefault(currency)
+	places, err := c.CurrencyDecimalPlaces(ctx, currency)
 	if err != nil {
 		return nil, err
 	}
-	return &transaction, nil
+	minorUnits, err := parseAmount(amount, places)
+	if err != nil {
+		return nil, err
+	}
+	return c.TransactionsCreateContext(ctx, minorUnits, currency, description, timestamp)
+}
+
+// TransactionsAddToday creates a transaction timestamped with the current time in `loc`
+// (local time is used if `loc` is nil), which is handy for quick expense entry.
+// The timestamp is sent in UTC, so it denotes the same instant (and thus the same calendar day in `loc`)
+// regardless of the server's time zone.
+func (c *APIClient) TransactionsAddToday(
+	ctx context.Context, amount int, currency string, description string, loc *time.Location,
+) (*Transaction, error) {
+	if loc == nil {
+		loc = time.Local
+	}
+	timestamp := c.currentTime().In(loc)
+	return c.TransactionsCreateContext(ctx, amount, currency, &description, &timestamp)
+}
+
+// ErrInvalidUUID is returned by methods taking a transaction UUID if it is empty, "." or "..".
+var ErrInvalidUUID = errors.New("invalid transaction UUID")
+
+// transactionPath returns path of the transaction with the given UUID. The UUID is escaped, so that it is always
+// a single path segment and cannot reach other endpoints (e.g. "../user" is not resolved to "/user").
+func transactionPath(uuid string) (string, error) {
+	if uuid == "" || uuid == "." || uuid == ".." {
+		return "", fmt.Errorf("%w: %q", ErrInvalidUUID, uuid)
+	}
+	return "/transactions/" + url.PathEscape(uuid), nil
 }
 
 func (c *APIClient) TransactionsReadOne(uuid string, currency *string) (*Transaction, error) {
-	var queryParams map[string]string
+	return c.TransactionsReadOneContext(context.Background(), uuid, currency)
+}
+
+// TransactionsReadOneContext is like TransactionsReadOne but uses the given context for the request.
+func (c *APIClient) TransactionsReadOneContext(ctx context.Context, uuid string, currency *string) (*Transaction, error) {
+	path, err := transactionPath(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	var queryParams url.Values
 	if currency != nil {
-		queryParams = make(map[string]string) // initialize the map only if it is needed
-		queryParams["currency"] = *currency
+		queryParams = make(url.Values) // initialize the map only if it is needed
+		queryParams.Set("currency", *currency)
 	}
 
 	transaction := Transaction{}
-	err := c.sendRequest(
+	err = c.sendRequest(
+		ctx,
 		http.MethodGet,
-		fmt.Sprintf("/transactions/%v", uuid),
+		path,
 		queryParams,
 		nil,
 		true,
@@ -300,19 +1082,65 @@ func (c *APIClient) TransactionsReadOne(uuid string, currency *string) (*Transac
 	return &transaction, nil
 }
 
+// TransactionsReadMany returns transactions between startTime and endTime, newest first
+// (use TransactionsReadManyFiltered with TransactionsFilter.Sort for other orders).
 func (c *APIClient) TransactionsReadMany(startTime time.Time, endTime *time.Time, currency *string) ([]*Transaction, error) {
-	queryParams := map[string]string{
-		"start_time": startTime.Format(timeFormat),
+	return c.TransactionsReadManyContext(context.Background(), startTime, endTime, currency)
+}
+
+// TransactionsReadManyContext is like TransactionsReadMany but uses the given context for the request.
+func (c *APIClient) TransactionsReadManyContext(
+	ctx context.Context, startTime time.Time, endTime *time.Time, currency *string,
+) ([]*Transaction, error) {
+	return c.TransactionsReadManyFilteredContext(ctx, startTime, endTime, currency, TransactionsFilter{})
+}
+
+// TransactionsReadManyFiltered is like TransactionsReadMany but returns only transactions satisfying the filter.
+func (c *APIClient) TransactionsReadManyFiltered(
+	startTime time.Time, endTime *time.Time, currency *string, filter TransactionsFilter,
+) ([]*Transaction, error) {
+	return c.TransactionsReadManyFilteredContext(context.Background(), startTime, endTime, currency, filter)
+}
+
+// TransactionsReadManyFilteredContext is like TransactionsReadManyFiltered but uses the given context for the request.
+func (c *APIClient) TransactionsReadManyFilteredContext(
+	ctx context.Context, startTime time.Time, endTime *time.Time, currency *string, filter TransactionsFilter,
+) ([]*Transaction, error) {
+	transactions, err := c.readTransactions(ctx, startTime, endTime, currency, filter, c.maxResults)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkResultsCount(len(transactions)); err != nil {
+		return nil, err
+	}
+	return filter.apply(transactions), nil
+}
+
+// readTransactions sends the request of TransactionsReadManyFilteredContext, asking the server for at most
+// maxResults+1 transactions if maxResults is positive. Unlike TransactionsReadManyFilteredContext,
+// it neither checks the number of returned transactions nor applies the client-side part of the filter,
+// so that helpers reading transactions page by page (see TransactionsIterator) are not limited by SetMaxResults.
+func (c *APIClient) readTransactions(
+	ctx context.Context, startTime time.Time, endTime *time.Time, currency *string, filter TransactionsFilter, maxResults int,
+) ([]*Transaction, error) {
+	queryParams := url.Values{
+		"start_time": {c.formatTime(startTime)},
 	}
 	if endTime != nil {
-		queryParams["end_time"] = (*endTime).Format(timeFormat)
+		queryParams.Set("end_time", c.formatTime(*endTime))
 	}
 	if currency != nil {
-		queryParams["currency"] = *currency
+		queryParams.Set("currency", c.currencyOrDefault(*currency))
+	}
+	if maxResults > 0 {
+		// request one transaction more than allowed to detect overflow:
+		queryParams.Set("limit", strconv.Itoa(maxResults+1))
 	}
+	filter.queryParams(queryParams)
 
 	transactions := make([]*Transaction, 0)
 	err := c.sendRequest(
+		ctx,
 		http.MethodGet,
 		"/transactions",
 		queryParams,
@@ -329,26 +1157,34 @@ func (c *APIClient) TransactionsReadMany(startTime time.Time, endTime *time.Time
 func (c *APIClient) TransactionsUpdate(
 	uuid string, newAmount *int, newCurrency *string, newDescription *string, newTimestamp *time.Time,
 ) (*Transaction, error) {
-	bodyParams := make(map[string]any)
-	if newAmount != nil {
-		bodyParams["new_amount"] = *newAmount
+	if err := c.checkWritable(); err != nil {
+		return nil, err
 	}
-	if newCurrency != nil {
-		bodyParams["new_currency"] = *newCurrency
+	if err := c.validateTimestampStrict(newTimestamp); err != nil {
+		return nil, err
 	}
-	if newDescription != nil {
-		bodyParams["new_description"] = *newDescription
+	path, err := transactionPath(uuid)
+	if err != nil {
+		return nil, err
+	}
+
+	requestBody := transactionUpdateRequest{
+		NewAmount:      newAmount,
+		NewCurrency:    newCurrency,
+		NewDescription: newDescription,
 	}
 	if newTimestamp != nil {
-		bodyParams["new_timestamp"] = (*newTimestamp).Format(timeFormat)
+		formatted := c.formatTime(*newTimestamp)
+		requestBody.NewTimestamp = &formatted
 	}
 
 	transaction := Transaction{}
-	err := c.sendRequest(
+	err = c.sendRequest(
+		context.Background(),
 		http.MethodPut,
-		fmt.Sprintf("/transactions/%v", uuid),
+		path,
 		nil,
-		bodyParams,
+		requestBody,
 		true,
 		&transaction,
 	)
@@ -359,10 +1195,24 @@ func (c *APIClient) TransactionsUpdate(
 }
 
 func (c *APIClient) TransactionsDelete(uuid string) (*Transaction, error) {
+	return c.TransactionsDeleteContext(context.Background(), uuid)
+}
+
+// TransactionsDeleteContext is like TransactionsDelete but uses the given context for the request.
+func (c *APIClient) TransactionsDeleteContext(ctx context.Context, uuid string) (*Transaction, error) {
+	if err := c.checkWritable(); err != nil {
+		return nil, err
+	}
+	path, err := transactionPath(uuid)
+	if err != nil {
+		return nil, err
+	}
+
 	transaction := Transaction{}
-	err := c.sendRequest(
+	err = c.sendRequest(
+		ctx,
 		http.MethodDelete,
-		fmt.Sprintf("/transactions/%v", uuid),
+		path,
 		nil,
 		nil,
 		true,
@@ -375,19 +1225,59 @@ func (c *APIClient) TransactionsDelete(uuid string) (*Transaction, error) {
 }
 
 func (c *APIClient) TransactionsReadSummary(currency string, startTime time.Time, endTime *time.Time) (*TransactionsSummary, error) {
-	queryParams := map[string]string{
-		"currency":   currency,
-		"start_time": startTime.Format(timeFormat),
+	return c.TransactionsReadSummaryContext(context.Background(), currency, startTime, endTime)
+}
+
+// TransactionsReadSummaryContext is like TransactionsReadSummary but uses the given context for the request.
+func (c *APIClient) TransactionsReadSummaryContext(
+	ctx context.Context, currency string, startTime time.Time, endTime *time.Time,
+) (*TransactionsSummary, error) {
+	return c.transactionsReadSummary(ctx, currency, nil, startTime, endTime)
+}
+
+// TransactionsReadSummaryConverted is like TransactionsReadSummary but asks the server to convert
+// income, outcome and total of the summary into currency `convertTo` (sent as the `convert_to` query param).
+// Conversion is performed by the server, which rounds converted amounts to whole minor units of `convertTo`,
+// so the converted totals may differ by a few minor units from converting the unconverted totals client-side.
+// If the server cannot convert the currency, its APIError is returned unchanged.
+func (c *APIClient) TransactionsReadSummaryConverted(
+	currency string, convertTo string, startTime time.Time, endTime *time.Time,
+) (*TransactionsSummary, error) {
+	return c.TransactionsReadSummaryConvertedContext(context.Background(), currency, convertTo, startTime, endTime)
+}
+
+// TransactionsReadSummaryConvertedContext is like TransactionsReadSummaryConverted
+// but uses the given context for the request.
+func (c *APIClient) TransactionsReadSummaryConvertedContext(
+	ctx context.Context, currency string, convertTo string, startTime time.Time, endTime *time.Time,
+) (*TransactionsSummary, error) {
+	return c.transactionsReadSummary(ctx, currency, &convertTo, startTime, endTime)
+}
+
+// summaryQueryParams returns query params of the summary request.
+func (c *APIClient) summaryQueryParams(currency string, convertTo *string, startTime time.Time, endTime *time.Time) url.Values {
+	queryParams := url.Values{
+		"currency":   {c.currencyOrDefault(currency)},
+		"start_time": {c.formatTime(startTime)},
 	}
 	if endTime != nil {
-		queryParams["end_time"] = (*endTime).Format(timeFormat)
+		queryParams.Set("end_time", c.formatTime(*endTime))
+	}
+	if convertTo != nil {
+		queryParams.Set("convert_to", *convertTo)
 	}
+	return queryParams
+}
 
+func (c *APIClient) transactionsReadSummary(
+	ctx context.Context, currency string, convertTo *string, startTime time.Time, endTime *time.Time,
+) (*TransactionsSummary, error) {
 	transactionsSummary := TransactionsSummary{}
 	err := c.sendRequest(
+		ctx,
 		http.MethodGet,
-		"/transactions/summary",
-		queryParams,
+		summaryPath,
+		c.summaryQueryParams(currency, convertTo, startTime, endTime),
 		nil,
 		true,
 		&transactionsSummary,
@@ -402,8 +1292,14 @@ func (c *APIClient) TransactionsReadSummary(currency string, startTime time.Time
 
 // CurrenciesRead returns slice of available currencies.
 func (c *APIClient) CurrenciesRead() ([]*Currency, error) {
+	return c.CurrenciesReadContext(context.Background())
+}
+
+// CurrenciesReadContext is like CurrenciesRead but uses the given context for the request.
+func (c *APIClient) CurrenciesReadContext(ctx context.Context) ([]*Currency, error) {
 	var currencies []*Currency
 	err := c.sendRequest(
+		ctx,
 		http.MethodGet,
 		"/currencies",
 		nil,
@@ -414,14 +1310,133 @@ func (c *APIClient) CurrenciesRead() ([]*Currency, error) {
 	if err != nil {
 		return nil, err
 	}
+	c.registerDecimalPlaces(currencies)
 	return currencies, nil
 }
 
+// Ping checks that groshi server is reachable and responds successfully, e.g. for readiness probes.
+// It sends a single unauthenticated request to the lightweight /currencies endpoint, which is never retried,
+// so that unreachable server (e.g. connection refused) is reported as fast as possible.
+// No token is required.
+func (c *APIClient) Ping(ctx context.Context) error {
+	return c.sendRequest(
+		withoutRetries(ctx),
+		http.MethodGet,
+		"/currencies",
+		nil,
+		nil,
+		false,
+		nil,
+	)
+}
+
+// ErrInvalidBaseURL is returned by NewClient when the base URL is not a valid http or https URL.
+var ErrInvalidBaseURL = errors.New("invalid base URL")
+
+// validateBaseURL returns ErrInvalidBaseURL if baseURL is empty, cannot be parsed,
+// has scheme other than http or https or has no host.
+func validateBaseURL(baseURL string) error {
+	if baseURL == "" {
+		return fmt.Errorf("%w: empty string", ErrInvalidBaseURL)
+	}
+	urlObject, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBaseURL, err)
+	}
+	if urlObject.Scheme != "http" && urlObject.Scheme != "https" {
+		return fmt.Errorf("%w: scheme must be http or https, got %q", ErrInvalidBaseURL, urlObject.Scheme)
+	}
+	if urlObject.Host == "" {
+		return fmt.Errorf("%w: no host in %q", ErrInvalidBaseURL, baseURL)
+	}
+	return nil
+}
+
+// NewClient creates a new APIClient instance configured with the given options and returns pointer to it.
+// It is the recommended method to produce APIClient. An error is returned if baseURL is empty
+// or is not a valid http or https URL. The base URL may have a path prefix (e.g. for a server behind
+// a reverse proxy), which is preserved when joined with endpoint paths, with or without a trailing slash. Example:
+//
+// client, err := NewClient("http://localhost:8080", WithToken(token), WithTimeout(30*time.Second))
+func NewClient(baseURL string, opts ...Option) (*APIClient, error) {
+	if err := validateBaseURL(baseURL); err != nil {
+		return nil, err
+	}
+
+	c := newAPIClient(baseURL)
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// NewAPIClientChecked is like NewAPIClient but validates the base URL first,
+// so that a typo is reported at construction time rather than on the first request.
+//
+// Deprecated: use NewClient with WithToken option.
+func NewAPIClientChecked(baseURL string, token string) (*APIClient, error) {
+	return NewClient(baseURL, WithToken(token))
+}
+
 // NewAPIClient creates a new APIClient instance and returns pointer to it.
-// It is the recommended method to produce APIClient.
+//
+// Deprecated: use NewClient with WithToken option, which also validates the base URL
+// and accepts other options.
 func NewAPIClient(baseURL string, token string) *APIClient {
+	c := newAPIClient(baseURL)
+	c.token = token
+	return c
+}
+
+// newDefaultTransport returns a clone of http.DefaultTransport or, if it was replaced by something
+// other than *http.Transport (e.g. by a mocking library), a new transport with the same defaults.
+func newDefaultTransport() *http.Transport {
+	if transport, ok := http.DefaultTransport.(*http.Transport); ok {
+		return transport.Clone()
+	}
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
+// newAPIClient returns APIClient with the default configuration.
+func newAPIClient(baseURL string) *APIClient {
+	transport := newDefaultTransport()
+	if transport.TLSClientConfig == nil {
+		transport.TLSClientConfig = &tls.Config{}
+	}
+	transport.TLSClientConfig.MinVersion = tls.VersionTLS12
+
 	return &APIClient{
 		baseURL: strings.TrimRight(baseURL, "/"),
-		token:   token,
+
+		httpClient: &http.Client{
+			Transport: transport,
+		},
+		transport: transport,
+		timeout:   defaultTimeout,
+
+		maxTimestampSkew: DefaultMaxTimestampSkew,
+
+		idempotencyHeader: DefaultIdempotencyHeader,
+
+		concurrency: defaultConcurrency,
+
+		userAgent: defaultUserAgent,
+
+		timeFormat: timeFormat,
+
+		maxResponseBytes: defaultMaxResponseBytes,
+
+		apiVersion: DefaultAPIVersion,
 	}
 }
@@ -0,0 +1,312 @@
+package go_groshi
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestClient returns a client with a token sending requests to a server serving them with handler.
+func newTestClient(t *testing.T, handler http.HandlerFunc, opts ...Option) *APIClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client, err := NewClient(server.URL, append([]Option{WithToken("token")}, opts...)...)
+	require.NoError(t, err)
+	return client
+}
+
+// writeJSON writes body as a successful JSON response.
+func writeJSON(w http.ResponseWriter, body string) {
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(body))
+}
+
+func TestBaseURLPathPrefix(t *testing.T) {
+	tests := []struct {
+		name     string
+		basePath string
+		wantPath string
+	}{
+		{"no path", "", "/user"},
+		{"root with trailing slash", "/", "/user"},
+		{"prefix", "/groshi/api", "/groshi/api/user"},
+		{"prefix with trailing slash", "/groshi/api/", "/groshi/api/user"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotPath = r.URL.Path
+				writeJSON(w, `{"username": "user"}`)
+			}))
+			defer server.Close()
+
+			client, err := NewClient(server.URL+tt.basePath, WithToken("token"))
+			require.NoError(t, err)
+			_, err = client.UserRead()
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantPath, gotPath)
+		})
+	}
+}
+
+func TestTransactionPathTraversal(t *testing.T) {
+	var gotPaths []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		gotPaths = append(gotPaths, r.URL.EscapedPath())
+		writeJSON(w, `{}`)
+	})
+
+	for _, uuid := range []string{"", ".", ".."} {
+		_, err := client.TransactionsDelete(uuid)
+		assert.ErrorIs(t, err, ErrInvalidUUID, "uuid %q", uuid)
+		_, err = client.TransactionsReadOne(uuid, nil)
+		assert.ErrorIs(t, err, ErrInvalidUUID, "uuid %q", uuid)
+	}
+	assert.Empty(t, gotPaths)
+
+	_, err := client.TransactionsDelete("../user")
+	require.NoError(t, err)
+	_, err = client.TransactionsReadOne("a/b", nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/transactions/..%2Fuser", "/transactions/a%2Fb"}, gotPaths)
+}
+
+func TestCloseClosesIdleConnectionsOfWrappedTransport(t *testing.T) {
+	var mutex sync.Mutex
+	states := make(map[net.Conn]http.ConnState)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, `{"username": "user"}`)
+	}))
+	server.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		states[conn] = state
+	}
+	server.Start()
+	defer server.Close()
+
+	wrapper := func(next http.RoundTripper) http.RoundTripper {
+		return roundTripperFunc(next.RoundTrip)
+	}
+	client, err := NewClient(server.URL, WithToken("token"), WithTransport(wrapper))
+	require.NoError(t, err)
+	_, err = client.UserRead()
+	require.NoError(t, err)
+	require.NoError(t, client.Close())
+
+	assert.Eventually(t, func() bool {
+		mutex.Lock()
+		defer mutex.Unlock()
+		for _, state := range states {
+			if state != http.StateClosed {
+				return false
+			}
+		}
+		return len(states) > 0
+	}, time.Second, 10*time.Millisecond)
+}
+
+// roundTripperFunc is a RoundTripper without CloseIdleConnections method, as most transport wrappers are.
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(request *http.Request) (*http.Response, error) {
+	return f(request)
+}
+
+func TestSetTokenConcurrentlyWithRequests(t *testing.T) {
+	tokens := []string{"token-1", "token-2", "token-3"}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, `{"username": "user"}`)
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				client.SetToken(tokens[(i+j)%len(tokens)])
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 25; j++ {
+				_, err := client.UserRead()
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Contains(t, tokens, client.Token())
+}
+
+func TestRequestWithoutToken(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		writeJSON(w, `{"username": "user"}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL)
+	require.NoError(t, err)
+	_, err = client.UserRead()
+	assert.ErrorIs(t, err, ErrNoToken)
+	assert.Zero(t, requests)
+}
+
+func TestRequestBodyAndContentType(t *testing.T) {
+	type capturedRequest struct {
+		contentType string
+		body        string
+	}
+	var captured capturedRequest
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		captured = capturedRequest{contentType: r.Header.Get("Content-Type"), body: string(body)}
+		writeJSON(w, `{"username": "user"}`)
+	})
+
+	_, err := client.UserRead()
+	require.NoError(t, err)
+	assert.Equal(t, capturedRequest{}, captured, "GET must have neither body nor Content-Type")
+
+	newUsername := "new-user"
+	_, err = client.UserUpdate(&newUsername, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "application/json; charset=utf-8", captured.contentType)
+	assert.JSONEq(t, `{"new_username": "new-user"}`, captured.body)
+}
+
+func TestRenameUserUsernameTaken(t *testing.T) {
+	status := http.StatusConflict
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		writeJSON(w, `{"error_message": "user with this username already exists", "error_details": []}`)
+	})
+
+	_, err := client.RenameUser("taken")
+	assert.ErrorIs(t, err, ErrUsernameTaken)
+	assert.ErrorIs(t, err, ErrConflict)
+	var apiErr APIError
+	require.True(t, errors.As(err, &apiErr), "error %v does not wrap APIError", err)
+	assert.Equal(t, "user with this username already exists", apiErr.ErrorMessage)
+
+	status = http.StatusUnprocessableEntity
+	_, err = client.RenameUser("")
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.NotErrorIs(t, err, ErrUsernameTaken)
+}
+
+func TestParseAPIError(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantMessage string
+		wantDetails []string
+		wantRawBody string
+	}{
+		{
+			"details",
+			`{"error_message": "invalid request", "error_details": ["amount: must not be zero"]}`,
+			"invalid request", []string{"amount: must not be zero"}, "",
+		},
+		{
+			"missing details",
+			`{"error_message": "invalid request"}`,
+			"invalid request", nil, "",
+		},
+		{
+			"null details",
+			`{"error_message": "invalid request", "error_details": null}`,
+			"invalid request", nil, "",
+		},
+		{
+			"non-string details",
+			`{"error_message": "invalid request", "error_details": [{"field": "amount"}, 42, "currency: unknown"]}`,
+			"invalid request", []string{`{"field": "amount"}`, "42", "currency: unknown"}, "",
+		},
+		{
+			"not an error model",
+			`<html>Bad Gateway</html>`,
+			"unexpected error response with status 400 Bad Request",
+			[]string{`response body: "<html>Bad Gateway</html>"`}, "<html>Bad Gateway</html>",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := parseAPIError(http.StatusBadRequest, []byte(tt.body))
+			assert.Equal(t, http.StatusBadRequest, apiErr.HTTPStatusCode)
+			assert.Equal(t, tt.wantMessage, apiErr.ErrorMessage)
+			assert.Equal(t, tt.wantDetails, apiErr.ErrorDetails)
+			assert.Equal(t, tt.wantRawBody, apiErr.RawBody)
+		})
+	}
+
+	apiErr := parseAPIError(http.StatusUnprocessableEntity, []byte(`{"error_message": "invalid", "error_details": ["amount: must not be zero", "oops"]}`))
+	assert.Equal(t, []FieldError{{Field: "amount", Message: "must not be zero"}}, apiErr.FieldErrors)
+	assert.ErrorIs(t, apiErr, ErrValidation)
+}
+
+func TestBaseContextCancellation(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	received := make(chan struct{}, 1)
+	baseCtx, cancel := context.WithCancel(context.Background())
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}, WithContext(baseCtx))
+
+	errs := make(chan error, 1)
+	go func() {
+		_, err := client.UserRead()
+		errs <- err
+	}()
+	<-received
+	cancel()
+
+	select {
+	case err := <-errs:
+		assert.ErrorIs(t, err, context.Canceled)
+	case <-time.After(time.Second):
+		t.Fatal("UserRead did not return after the base context was cancelled")
+	}
+
+	// requests are not sent at all once the base context is done:
+	_, err := client.UserRead()
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Empty(t, received)
+}
+
+func TestNewClientWithReplacedDefaultTransport(t *testing.T) {
+	defaultTransport := http.DefaultTransport
+	t.Cleanup(func() { http.DefaultTransport = defaultTransport })
+	http.DefaultTransport = roundTripperFunc(func(request *http.Request) (*http.Response, error) {
+		return nil, errors.New("unexpected request")
+	})
+
+	client, err := NewClient("http://groshi.example", WithToken("token"))
+	require.NoError(t, err)
+	require.NotNil(t, client.transport)
+	assert.Equal(t, uint16(tls.VersionTLS12), client.transport.TLSClientConfig.MinVersion)
+
+	assert.NotPanics(t, func() { NewAPIClient("http://groshi.example", "token") })
+}
@@ -0,0 +1,74 @@
+package go_groshi
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// acceptEncoding is the value of the Accept-Encoding header sent with every request.
+const acceptEncoding = "gzip, deflate"
+
+// SetRequestCompression enables or disables gzip compression of request bodies (sent with Content-Encoding: gzip),
+// which saves bandwidth when uploading many transactions over slow links. Requests without a body are never compressed.
+// The server (or a proxy in front of it) must support gzip-encoded request bodies, otherwise requests fail.
+// Compression is disabled by default.
+func (c *APIClient) SetRequestCompression(compress bool) {
+	c.compressRequests = compress
+}
+
+// gzipBody returns body compressed with gzip.
+func gzipBody(body []byte) ([]byte, error) {
+	var buffer bytes.Buffer
+	writer := gzip.NewWriter(&buffer)
+	if _, err := writer.Write(body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// decodeResponseBody returns the body of the response decompressed according to its Content-Encoding header
+// (gzip or deflate, i.e. zlib format). Since the client sets the Accept-Encoding header itself,
+// http.Transport does not decompress responses transparently. After decoding, the Content-Encoding
+// and Content-Length headers are removed, as they no longer describe the body.
+// Empty bodies (e.g. of 204 No Content and 304 Not Modified responses) are returned as is, whatever the encoding.
+// Closing the returned reader does not close the original body.
+func decodeResponseBody(response *http.Response) (io.ReadCloser, error) {
+	encoding := strings.ToLower(strings.TrimSpace(response.Header.Get("Content-Encoding")))
+	if encoding == "" || encoding == "identity" {
+		return io.NopCloser(response.Body), nil
+	}
+
+	buffered := bufio.NewReader(response.Body)
+	if _, err := buffered.Peek(1); err == io.EOF {
+		return io.NopCloser(buffered), nil
+	}
+
+	var body io.ReadCloser
+	var err error
+	switch encoding {
+	case "gzip", "x-gzip":
+		body, err = gzip.NewReader(buffered)
+	case "deflate":
+		body, err = zlib.NewReader(buffered)
+	default:
+		return nil, fmt.Errorf("unsupported Content-Encoding of response: %q", encoding)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot decode %v response body: %w", encoding, err)
+	}
+
+	response.Header.Del("Content-Encoding")
+	response.Header.Del("Content-Length")
+	response.ContentLength = -1
+	response.Uncompressed = true
+	return body, nil
+}
@@ -0,0 +1,76 @@
+package go_groshi
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEmptySuccessfulResponses(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		encoding string
+	}{
+		{"204", http.StatusNoContent, ""},
+		{"204 gzip", http.StatusNoContent, "gzip"},
+		{"200 empty", http.StatusOK, ""},
+		{"200 empty deflate", http.StatusOK, "deflate"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+				}
+				w.WriteHeader(tt.status)
+			})
+
+			transaction, err := client.TransactionsDelete("uuid")
+			require.NoError(t, err)
+			assert.Equal(t, &Transaction{}, transaction)
+		})
+	}
+}
+
+func TestCompressedResponses(t *testing.T) {
+	compress := func(newWriter func(io.Writer) io.WriteCloser, data string) []byte {
+		var buffer bytes.Buffer
+		writer := newWriter(&buffer)
+		_, err := writer.Write([]byte(data))
+		require.NoError(t, err)
+		require.NoError(t, writer.Close())
+		return buffer.Bytes()
+	}
+	newGzipWriter := func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) }
+	newZlibWriter := func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) }
+
+	tests := []struct {
+		encoding string
+		body     []byte
+	}{
+		{"gzip", compress(newGzipWriter, `{"username": "user"}`)},
+		{"deflate", compress(newZlibWriter, `{"username": "user"}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.encoding, func(t *testing.T) {
+			var acceptEncodingHeader string
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				acceptEncodingHeader = r.Header.Get("Accept-Encoding")
+				w.Header().Set("Content-Encoding", tt.encoding)
+				_, _ = w.Write(tt.body)
+			})
+
+			user, err := client.UserRead()
+			require.NoError(t, err)
+			assert.Equal(t, "user", user.Username)
+			assert.Equal(t, acceptEncoding, acceptEncodingHeader)
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package go_groshi
+
+import (
+	"context"
+	"sync"
+)
+
+// defaultConcurrency is the default maximum number of requests sent in parallel by helpers which fan out.
+const defaultConcurrency = 4
+
+// SetConcurrency sets the maximum number of requests sent in parallel by helpers which fan out,
+// such as TransactionsCreateMany or AnnualMatrix. The default is 4; values less than 1 mean 1.
+func (c *APIClient) SetConcurrency(n int) {
+	c.concurrency = n
+}
+
+// forEachConcurrently calls fn for every index in [0, n) using at most `limit` goroutines at once.
+// It stops scheduling new calls as soon as ctx is done or fn returns an error,
+// and returns the first error encountered (or the context error).
+func forEachConcurrently(ctx context.Context, n int, limit int, fn func(ctx context.Context, i int) error) error {
+	if limit < 1 {
+		limit = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	setErr := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+
+	semaphore := make(chan struct{}, limit)
+	for i := 0; i < n; i++ {
+		// select picks at random when both cases are ready, so the context is checked again
+		// after acquiring the semaphore; otherwise a done context could go unreported.
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			setErr(err)
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			if err := fn(ctx, i); err != nil {
+				setErr(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return firstErr
+}
@@ -0,0 +1,39 @@
+package go_groshi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestForEachConcurrentlyCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// select picks at random between ready cases, so repeat to exercise both of them.
+	for run := 0; run < 100; run++ {
+		err := forEachConcurrently(ctx, 10, 4, func(ctx context.Context, i int) error {
+			return nil
+		})
+		require.ErrorIs(t, err, context.Canceled)
+	}
+}
+
+func TestTransactionsByISOWeekCancelledContext(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, `{"currency": "USD", "income": 0, "outcome": 0, "total": 0, "transactions_count": 0}`)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for run := 0; run < 100; run++ {
+		summaries, err := client.TransactionsByISOWeek(ctx, "USD", 2023, time.UTC)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, summaries)
+	}
+}
@@ -0,0 +1,79 @@
+package go_groshi
+
+import (
+	"context"
+	"net/http"
+	"time"
+)
+
+type requestHeadersContextKey struct{}
+
+// withRequestHeaders returns a copy of ctx carrying additional headers of requests sent with it.
+func withRequestHeaders(ctx context.Context, header http.Header) context.Context {
+	return context.WithValue(ctx, requestHeadersContextKey{}, header)
+}
+
+// requestHeadersFromContext returns headers stored in ctx by withRequestHeaders.
+func requestHeadersFromContext(ctx context.Context) http.Header {
+	header, _ := ctx.Value(requestHeadersContextKey{}).(http.Header)
+	return header
+}
+
+// cacheValidators are validators of a response used to send conditional requests.
+type cacheValidators struct {
+	etag         string
+	lastModified string
+}
+
+// TransactionsReadSummaryConditional is like TransactionsReadSummaryContext but sends a conditional request,
+// which cuts bandwidth of frequent polling. The ETag and Last-Modified headers of the previous response
+// to the same query (currency and time range) are remembered by the client and sent as If-None-Match and
+// If-Modified-Since; if the server responds with 304 Not Modified, nil summary and notModified=true are returned,
+// meaning that the previously returned summary is still valid. If the server does not send validators,
+// every call returns the summary as TransactionsReadSummaryContext does.
+func (c *APIClient) TransactionsReadSummaryConditional(
+	ctx context.Context, currency string, startTime time.Time, endTime *time.Time,
+) (summary *TransactionsSummary, notModified bool, err error) {
+	queryParams := c.summaryQueryParams(currency, nil, startTime, endTime)
+	key := queryParams.Encode()
+
+	c.validatorsMutex.Lock()
+	validators, ok := c.validators[key]
+	c.validatorsMutex.Unlock()
+
+	if ok {
+		header := make(http.Header)
+		if validators.etag != "" {
+			header.Set("If-None-Match", validators.etag)
+		}
+		if validators.lastModified != "" {
+			header.Set("If-Modified-Since", validators.lastModified)
+		}
+		ctx = withRequestHeaders(ctx, header)
+	}
+
+	summary = &TransactionsSummary{}
+	meta, err := c.sendRequestMeta(ctx, http.MethodGet, summaryPath, queryParams, nil, true, summary)
+	if err != nil {
+		return nil, false, err
+	}
+	if meta.StatusCode == http.StatusNotModified {
+		return nil, true, nil
+	}
+
+	validators = cacheValidators{
+		etag:         meta.Header.Get("ETag"),
+		lastModified: meta.Header.Get("Last-Modified"),
+	}
+	c.validatorsMutex.Lock()
+	defer c.validatorsMutex.Unlock()
+	if validators.etag == "" && validators.lastModified == "" {
+		delete(c.validators, key)
+	} else {
+		if c.validators == nil {
+			c.validators = make(map[string]cacheValidators)
+		}
+		c.validators[key] = validators
+	}
+	return summary, false, nil
+}
@@ -0,0 +1,192 @@
+package go_groshi
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// csvColumns are the columns of the CSV format of transactions, as named in the header row.
+// The amount column contains integer amounts in minor units, timestamp is RFC 3339 and may be empty
+// (server's current time is used then). Other columns, such as uuid, are ignored on import.
+var csvColumns = []string{"timestamp", "amount", "currency", "description"}
+
+// ErrImportAborted is set as the error of CSV rows which were not imported because the import was stopped.
+var ErrImportAborted = errors.New("import aborted before the row was processed")
+
+// CSVRowResult is the result of importing a single row of CSV.
+type CSVRowResult struct {
+	Line int    // line number of the row in the input, the header being line 1
+	UUID string // UUID of the created transaction, empty if Err is set
+	Err  error
+}
+
+// csvRow is a parsed row of CSV.
+type csvRow struct {
+	line  int
+	input TransactionInput
+	err   error
+}
+
+// readCSVRows reads CSV with the header row from r. Errors of individual rows are stored in the rows,
+// the returned error is set only if the input cannot be read at all.
+func readCSVRows(r io.Reader) ([]csvRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1 // checked per row
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("CSV header row is missing")
+		}
+		return nil, err
+	}
+	indices := make(map[string]int, len(header))
+	for i, column := range header {
+		indices[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, column := range csvColumns {
+		if _, ok := indices[column]; !ok {
+			return nil, fmt.Errorf("CSV header has no %q column", column)
+		}
+	}
+
+	rows := make([]csvRow, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if !errors.As(err, &parseErr) {
+				return nil, err
+			}
+			rows = append(rows, csvRow{line: parseErr.Line, err: err})
+			continue
+		}
+		line, _ := reader.FieldPos(0)
+		if len(record) != len(header) {
+			rows = append(rows, csvRow{line: line, err: fmt.Errorf(
+				"row has %v fields, header has %v", len(record), len(header),
+			)})
+			continue
+		}
+
+		input, err := parseCSVRecord(record, indices)
+		rows = append(rows, csvRow{line: line, input: input, err: err})
+	}
+	return rows, nil
+}
+
+// parseCSVRecord parses transaction input from the CSV record given indices of the columns.
+func parseCSVRecord(record []string, indices map[string]int) (TransactionInput, error) {
+	input := TransactionInput{Currency: NormalizeCurrency(record[indices["currency"]])}
+
+	amount, err := strconv.Atoi(strings.TrimSpace(record[indices["amount"]]))
+	if err != nil {
+		return input, fmt.Errorf("invalid amount: %w", err)
+	}
+	input.Amount = amount
+
+	if value := strings.TrimSpace(record[indices["timestamp"]]); value != "" {
+		timestamp, err := parseTime(value)
+		if err != nil {
+			return input, err
+		}
+		input.Timestamp = &timestamp
+	}
+
+	if description := record[indices["description"]]; description != "" {
+		input.Description = &description
+	}
+	return input, nil
+}
+
+// TransactionsImportCSV creates transactions from CSV read from r and returns results of all rows in order.
+// The first row must be the header naming the columns: timestamp, amount (integer, in minor units),
+// currency and description, in any order; an empty timestamp means the server's current time,
+// and an empty currency means the default one (see SetDefaultCurrency).
+// Currencies are validated against the server's currencies (requested once and cached by the client),
+// descriptions exceeding the server's limits (see ServerLimits) are treated according to SetDescriptionPolicy,
+// and transactions are created with up to SetConcurrency requests in parallel.
+//
+// By default, a failure of one row does not prevent others from being imported, and the returned error
+// is set only if the CSV cannot be read or currencies or limits cannot be requested.
+// If stopOnError is true, nothing is created unless all rows are valid, the import stops at the first failed row,
+// and the error of that row is returned; rows which were not processed get ErrImportAborted.
+func (c *APIClient) TransactionsImportCSV(ctx context.Context, r io.Reader, stopOnError bool) ([]CSVRowResult, error) {
+	rows, err := readCSVRows(r)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := c.currencyCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	limits, err := c.ServerLimits(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]CSVRowResult, len(rows))
+	for i, row := range rows {
+		results[i] = CSVRowResult{Line: row.line, Err: row.err}
+		if row.err != nil {
+			continue
+		}
+		rows[i].input.Currency = c.currencyOrDefault(row.input.Currency)
+		if _, ok := codes[rows[i].input.Currency]; !ok {
+			results[i].Err = fmt.Errorf("currency %v is not available", rows[i].input.Currency)
+			continue
+		}
+		rows[i].input.Description, results[i].Err = c.enforceDescriptionLimit(row.input.Description, limits)
+	}
+
+	if stopOnError {
+		for i, result := range results {
+			if result.Err == nil {
+				continue
+			}
+			for j := range results {
+				if j != i && results[j].Err == nil {
+					results[j].Err = ErrImportAborted
+				}
+			}
+			return results, fmt.Errorf("line %v: %w", result.Line, result.Err)
+		}
+	}
+
+	attempted := make([]bool, len(rows))
+	err = forEachConcurrently(ctx, len(rows), c.concurrency, func(ctx context.Context, i int) error {
+		if results[i].Err != nil {
+			return nil // invalid rows are only possible if stopOnError is false
+		}
+		attempted[i] = true
+
+		input := rows[i].input
+		transaction, err := c.TransactionsCreateContext(ctx, input.Amount, input.Currency, input.Description, input.Timestamp)
+		if err != nil {
+			results[i].Err = err
+			if stopOnError {
+				return fmt.Errorf("line %v: %w", results[i].Line, err)
+			}
+			return nil
+		}
+		results[i].UUID = transaction.UUID
+		return nil
+	})
+
+	for i := range results {
+		if results[i].Err == nil && !attempted[i] {
+			results[i].Err = ErrImportAborted
+		}
+	}
+	return results, err
+}
@@ -0,0 +1,143 @@
+package go_groshi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// defaultDecimalPlaces is the number of decimal places assumed for currencies unknown to both the server and the library.
+const defaultDecimalPlaces = 2
+
+// iso4217DecimalPlaces contains numbers of decimal places of ISO 4217 currencies
+// which differ from defaultDecimalPlaces. It is used only when the server does not provide them.
+var iso4217DecimalPlaces = map[string]int{
+	"BIF": 0, "CLP": 0, "DJF": 0, "GNF": 0, "ISK": 0, "JPY": 0, "KMF": 0, "KRW": 0, "PYG": 0,
+	"RWF": 0, "UGX": 0, "UYI": 0, "VND": 0, "VUV": 0, "XAF": 0, "XOF": 0, "XPF": 0,
+	"BHD": 3, "IQD": 3, "JOD": 3, "KWD": 3, "LYD": 3, "OMR": 3, "TND": 3,
+	"CLF": 4, "UYW": 4,
+}
+
+// ISODecimalPlaces returns number of decimal places of the currency according to the built-in ISO 4217 table,
+// or two for currencies unknown to it. Use APIClient.CurrencyDecimalPlaces to prefer the value provided by the server.
+func ISODecimalPlaces(code string) int {
+	if places, ok := iso4217DecimalPlaces[NormalizeCurrency(code)]; ok {
+		return places
+	}
+	return defaultDecimalPlaces
+}
+
+// currencyDecimalPlaces returns DecimalPlaces of the currency if it is set, otherwise ISODecimalPlaces of its code.
+func currencyDecimalPlaces(currency *Currency) int {
+	if currency.DecimalPlaces != nil {
+		return *currency.DecimalPlaces
+	}
+	return ISODecimalPlaces(currency.Code)
+}
+
+// registerDecimalPlaces remembers numbers of decimal places provided by the server for the currencies.
+func (c *APIClient) registerDecimalPlaces(currencies []*Currency) {
+	for _, currency := range currencies {
+		if currency.DecimalPlaces != nil {
+			c.serverDecimalPlaces.Store(NormalizeCurrency(currency.Code), *currency.DecimalPlaces)
+		}
+	}
+}
+
+// decimalPlaces returns number of decimal places of the currency, preferring the value provided by the server
+// (if currencies were read by this client before), then ISODecimalPlaces.
+func (c *APIClient) decimalPlaces(code string) int {
+	if places, ok := c.serverDecimalPlaces.Load(NormalizeCurrency(code)); ok {
+		return places.(int)
+	}
+	return ISODecimalPlaces(code)
+}
+
+// NormalizeCurrency returns currency code in the canonical form used by groshi API:
+// upper-case and without surrounding whitespace (e.g. " usd" becomes "USD").
+func NormalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// SetDefaultCurrency sets the currency used by TransactionsCreate, TransactionsReadSummary and their variants
+// when an empty currency is passed to them. TransactionsReadMany and its variants use it when
+// the currency is a pointer to an empty string, while nil still means transactions in all currencies.
+// A non-empty currency argument always overrides the default. Empty code (the default) disables the fallback.
+func (c *APIClient) SetDefaultCurrency(code string) {
+	c.defaultCurrency = NormalizeCurrency(code)
+}
+
+// currencyOrDefault returns currency, or the default currency set by SetDefaultCurrency if currency is empty.
+func (c *APIClient) currencyOrDefault(currency string) string {
+	if currency == "" {
+		return c.defaultCurrency
+	}
+	return currency
+}
+
+// cachedCurrencies returns currencies available on the server.
+// The list is requested once and then reused for the whole lifetime of the client.
+func (c *APIClient) cachedCurrencies(ctx context.Context) ([]*Currency, error) {
+	c.currenciesMutex.Lock()
+	defer c.currenciesMutex.Unlock()
+
+	if c.currencies == nil {
+		currencies, err := c.CurrenciesReadContext(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.currencies = currencies
+	}
+	return c.currencies, nil
+}
+
+// currencyCodes returns set of normalized codes of currencies available on the server.
+func (c *APIClient) currencyCodes(ctx context.Context) (map[string]struct{}, error) {
+	currencies, err := c.cachedCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	codes := make(map[string]struct{}, len(currencies))
+	for _, currency := range currencies {
+		codes[NormalizeCurrency(currency.Code)] = struct{}{}
+	}
+	return codes, nil
+}
+
+// CurrencyDecimalPlaces returns number of decimal places (the exponent of the minor unit) of the currency.
+// The value provided by the server is used if available, otherwise the built-in ISO 4217 table is consulted,
+// and currencies unknown to both are assumed to have two decimal places.
+func (c *APIClient) CurrencyDecimalPlaces(ctx context.Context, code string) (int, error) {
+	if _, err := c.cachedCurrencies(ctx); err != nil {
+		return 0, err
+	}
+	return c.decimalPlaces(code), nil
+}
+
+// ErrCurrencyNotFound is returned by CurrencyByCode when the currency is not available on the server.
+var ErrCurrencyNotFound = errors.New("currency not found")
+
+// CurrencyByCode returns the currency with the given code (compared after NormalizeCurrency)
+// from the list of available currencies, which is requested once and cached by the client.
+// The returned value is a copy, and its DecimalPlaces is always set: if the server does not provide it,
+// the value of CurrencyDecimalPlaces is used.
+func (c *APIClient) CurrencyByCode(ctx context.Context, code string) (*Currency, error) {
+	currencies, err := c.cachedCurrencies(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	code = NormalizeCurrency(code)
+	for _, currency := range currencies {
+		if NormalizeCurrency(currency.Code) != code {
+			continue
+		}
+		result := *currency
+		places := c.decimalPlaces(code) // the server's value if provided
+		result.DecimalPlaces = &places
+		return &result, nil
+	}
+	return nil, fmt.Errorf("%w: %v", ErrCurrencyNotFound, code)
+}
@@ -0,0 +1,40 @@
+package go_groshi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecimalPlacesAreKeptPerClient(t *testing.T) {
+	customCurrencies := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, `[{"code": "JPY", "symbol": "¥", "decimal_places": 2}]`)
+	})
+	isoCurrencies := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, `[{"code": "JPY", "symbol": "¥"}]`)
+	})
+
+	places, err := customCurrencies.CurrencyDecimalPlaces(context.Background(), "JPY")
+	require.NoError(t, err)
+	assert.Equal(t, 2, places)
+
+	places, err = isoCurrencies.CurrencyDecimalPlaces(context.Background(), "JPY")
+	require.NoError(t, err)
+	assert.Equal(t, 0, places)
+
+	// package-level helpers are not affected by currencies read by clients:
+	assert.Equal(t, 0, ISODecimalPlaces("JPY"))
+	assert.Equal(t, "1234 JPY", NewMoney(1234, "JPY").String())
+	amount, err := ParseAmount("1234", &Currency{Code: "JPY"})
+	require.NoError(t, err)
+	assert.Equal(t, 1234, amount)
+
+	currency, err := customCurrencies.CurrencyByCode(context.Background(), "jpy")
+	require.NoError(t, err)
+	amount, err = ParseAmount("12.34", currency)
+	require.NoError(t, err)
+	assert.Equal(t, 1234, amount)
+}
@@ -0,0 +1,66 @@
+package go_groshi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// ErrResumePointNotFound is returned by ExportTransactionsNDJSON when the transaction to resume after is not found.
+var ErrResumePointNotFound = errors.New("transaction to resume export after is not found")
+
+// sortTransactions sorts transactions by timestamp and then by UUID, which gives a stable order.
+func sortTransactions(transactions []*Transaction) {
+	sort.SliceStable(transactions, func(i, j int) bool {
+		if !transactions[i].Timestamp.Equal(transactions[j].Timestamp) {
+			return transactions[i].Timestamp.Before(transactions[j].Timestamp)
+		}
+		return transactions[i].UUID < transactions[j].UUID
+	})
+}
+
+// ExportTransactionsNDJSON writes transactions between startTime and endTime to w as newline-delimited JSON,
+// one transaction per line, ordered by timestamp and then by UUID. Transactions are read page by page
+// with TransactionsIterate, so that exports of large accounts are streamed rather than held in memory.
+// If resumeAfter is not nil, transactions up to and including the one with that UUID are skipped,
+// so that an interrupted export can be continued from the returned lastUUID
+// (ErrResumePointNotFound is returned if there is no such transaction in the range).
+// lastUUID is the UUID of the last transaction written, or resumeAfter if nothing was written.
+// It is valid even if an error is returned while writing.
+func (c *APIClient) ExportTransactionsNDJSON(
+	ctx context.Context, w io.Writer, startTime time.Time, endTime time.Time, resumeAfter *string,
+) (lastUUID string, err error) {
+	resumed := resumeAfter == nil
+	if resumeAfter != nil {
+		lastUUID = *resumeAfter
+	}
+
+	encoder := json.NewEncoder(w)
+	iterator := c.TransactionsIterateContext(ctx, startTime, endTime)
+	for iterator.Next() {
+		transaction := iterator.Transaction()
+		if !resumed {
+			resumed = transaction.UUID == *resumeAfter
+			continue
+		}
+
+		if err := ctx.Err(); err != nil {
+			return lastUUID, err
+		}
+		if err := encoder.Encode(transaction); err != nil {
+			return lastUUID, err
+		}
+		lastUUID = transaction.UUID
+	}
+	if err := iterator.Err(); err != nil {
+		return lastUUID, err
+	}
+	if !resumed {
+		return lastUUID, fmt.Errorf("%w: %v", ErrResumePointNotFound, *resumeAfter)
+	}
+	return lastUUID, nil
+}
@@ -0,0 +1,56 @@
+package go_groshi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportTransactionsNDJSONStreamsPages(t *testing.T) {
+	startTime := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	transactions := make([]*Transaction, 0)
+	for i := 0; i < 5; i++ {
+		transactions = append(transactions, &Transaction{
+			UUID:      string(rune('a' + i)),
+			Amount:    100,
+			Currency:  "USD",
+			Timestamp: startTime.AddDate(0, i, 0), // one transaction per page
+		})
+	}
+	client := newLimitedServerClient(t, DefaultLimits, transactions)
+	client.SetMaxResults(2) // the whole range exceeds it, pages do not
+
+	readUUIDs := func(output []byte) []string {
+		uuids := make([]string, 0)
+		decoder := json.NewDecoder(bytes.NewReader(output))
+		for decoder.More() {
+			var transaction Transaction
+			require.NoError(t, decoder.Decode(&transaction))
+			uuids = append(uuids, transaction.UUID)
+		}
+		return uuids
+	}
+	endTime := startTime.AddDate(1, 0, 0)
+
+	var output bytes.Buffer
+	lastUUID, err := client.ExportTransactionsNDJSON(context.Background(), &output, startTime, endTime, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "e", lastUUID)
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, readUUIDs(output.Bytes()))
+
+	output.Reset()
+	resumeAfter := "b"
+	lastUUID, err = client.ExportTransactionsNDJSON(context.Background(), &output, startTime, endTime, &resumeAfter)
+	require.NoError(t, err)
+	assert.Equal(t, "e", lastUUID)
+	assert.Equal(t, []string{"c", "d", "e"}, readUUIDs(output.Bytes()))
+
+	missing := "x"
+	_, err = client.ExportTransactionsNDJSON(context.Background(), &output, startTime, endTime, &missing)
+	assert.ErrorIs(t, err, ErrResumePointNotFound)
+}
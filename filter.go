@@ -0,0 +1,191 @@
+package go_groshi
+
+import (
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TransactionsFilter contains optional filters of TransactionsReadManyFiltered.
+// Filters are sent to the server as query params; since a server may ignore them,
+// they are also applied by the client to the received transactions.
+// Likewise, transactions are sorted by the client, so the requested order is honored
+// even if the server does not support the `sort` query param.
+type TransactionsFilter struct {
+	// DescriptionContains, if not nil, selects transactions whose description contains the given substring
+	// (sent as the `description_contains` query param).
+	DescriptionContains *string
+
+	// CaseSensitive makes DescriptionContains match case-sensitively. By default, case is ignored.
+	CaseSensitive bool
+
+	// MinAmount and MaxAmount, if not nil, select transactions with amounts (in minor units, as Transaction.Amount)
+	// in range [MinAmount, MaxAmount], both bounds inclusive (sent as `min_amount` and `max_amount` query params).
+	// Note that outcome amounts are negative: use MaxAmount of -10000 to find expenses of 100.00 and larger.
+	MinAmount *int
+	MaxAmount *int
+
+	// Tags, if not empty, selects transactions having at least one of the given tags, compared case-sensitively
+	// (sent as repeated `tag` query params). Transactions of servers which do not support tags have no tags,
+	// so no transactions are selected by the filter then.
+	Tags []string
+
+	// Fields, if not empty, requests only the given fields of transactions (JSON names such as "amount"
+	// and "currency", sent as the comma-separated `fields` query param), which reduces the size of responses.
+	// Other fields of the returned transactions are left zero-valued and must not be mistaken for real data.
+	// Fields needed by the client to apply other filters and the sort order, as well as "uuid", are always requested.
+	// Servers which do not support the param return all fields.
+	Fields []string
+
+	// Sort is the order of returned transactions (sent as the `sort` query param).
+	// The zero value is SortTimestampDesc.
+	Sort SortOption
+}
+
+// SortOption is the order of transactions returned by TransactionsReadManyFiltered.
+// Transactions with equal sort keys are ordered by UUID, so the order is stable between calls.
+type SortOption int
+
+const (
+	SortTimestampDesc SortOption = iota // newest first, the default
+	SortTimestampAsc                    // oldest first
+	SortCreatedAtDesc                   // most recently created first
+	SortCreatedAtAsc                    // least recently created first
+)
+
+// String returns the value of the `sort` query param for the option, e.g. "-timestamp" for SortTimestampDesc.
+func (o SortOption) String() string {
+	switch o {
+	case SortTimestampAsc:
+		return "timestamp"
+	case SortCreatedAtDesc:
+		return "-created_at"
+	case SortCreatedAtAsc:
+		return "created_at"
+	default:
+		return "-timestamp"
+	}
+}
+
+// sort sorts transactions according to the option.
+func (o SortOption) sort(transactions []*Transaction) {
+	key := func(transaction *Transaction) time.Time {
+		if o == SortCreatedAtDesc || o == SortCreatedAtAsc {
+			return transaction.CreatedAt
+		}
+		return transaction.Timestamp
+	}
+	descending := o != SortTimestampAsc && o != SortCreatedAtAsc
+
+	sort.SliceStable(transactions, func(i, j int) bool {
+		a, b := key(transactions[i]), key(transactions[j])
+		if a.Equal(b) {
+			return transactions[i].UUID < transactions[j].UUID
+		}
+		if descending {
+			return a.After(b)
+		}
+		return a.Before(b)
+	})
+}
+
+// queryParams adds query params of the filter to the given values.
+func (f TransactionsFilter) queryParams(queryParams url.Values) {
+	if f.MinAmount != nil {
+		queryParams.Set("min_amount", strconv.Itoa(*f.MinAmount))
+	}
+	if f.MaxAmount != nil {
+		queryParams.Set("max_amount", strconv.Itoa(*f.MaxAmount))
+	}
+	for _, tag := range f.Tags {
+		queryParams.Add("tag", tag)
+	}
+	if len(f.Fields) > 0 {
+		queryParams.Set("fields", strings.Join(f.requestedFields(), ","))
+	}
+	queryParams.Set("sort", f.Sort.String())
+	if f.DescriptionContains != nil {
+		queryParams.Set("description_contains", *f.DescriptionContains)
+		if f.CaseSensitive {
+			queryParams.Set("case_sensitive", "true")
+		}
+	}
+}
+
+// requestedFields returns Fields along with fields needed to apply the filter client-side, without duplicates.
+func (f TransactionsFilter) requestedFields() []string {
+	fields := append([]string{"uuid"}, f.Fields...)
+	if f.DescriptionContains != nil {
+		fields = append(fields, "description")
+	}
+	if f.MinAmount != nil || f.MaxAmount != nil {
+		fields = append(fields, "amount")
+	}
+	if len(f.Tags) > 0 {
+		fields = append(fields, "tags")
+	}
+	if f.Sort == SortCreatedAtDesc || f.Sort == SortCreatedAtAsc {
+		fields = append(fields, "created_at")
+	} else {
+		fields = append(fields, "timestamp")
+	}
+
+	seen := make(map[string]struct{}, len(fields))
+	unique := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if _, ok := seen[field]; !ok {
+			seen[field] = struct{}{}
+			unique = append(unique, field)
+		}
+	}
+	return unique
+}
+
+// matches reports whether the transaction satisfies the filter.
+func (f TransactionsFilter) matches(transaction *Transaction) bool {
+	if f.MinAmount != nil && transaction.Amount < *f.MinAmount {
+		return false
+	}
+	if f.MaxAmount != nil && transaction.Amount > *f.MaxAmount {
+		return false
+	}
+	if len(f.Tags) > 0 && !hasAnyTag(transaction, f.Tags) {
+		return false
+	}
+	if f.DescriptionContains != nil {
+		description, substring := transaction.Description, *f.DescriptionContains
+		if !f.CaseSensitive {
+			description, substring = strings.ToLower(description), strings.ToLower(substring)
+		}
+		if !strings.Contains(description, substring) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasAnyTag reports whether the transaction has at least one of the tags.
+func hasAnyTag(transaction *Transaction, tags []string) bool {
+	for _, tag := range tags {
+		for _, transactionTag := range transaction.Tags {
+			if transactionTag == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// apply returns transactions satisfying the filter, sorted according to it.
+func (f TransactionsFilter) apply(transactions []*Transaction) []*Transaction {
+	filtered := make([]*Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		if f.matches(transaction) {
+			filtered = append(filtered, transaction)
+		}
+	}
+	f.Sort.sort(filtered)
+	return filtered
+}
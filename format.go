@@ -0,0 +1,100 @@
+package go_groshi
+
+import (
+	"math"
+	"unicode"
+	"unicode/utf8"
+
+	xcurrency "golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/message"
+	"golang.org/x/text/number"
+)
+
+// symbolPlacement is the position of the currency symbol in CLDR currency patterns of a locale.
+type symbolPlacement int
+
+const (
+	symbolBefore      symbolPlacement = iota // "¤#,##0.00", e.g. "$1,234.56"
+	symbolBeforeSpace                        // "¤ #,##0.00", e.g. "€ 1.234,56"
+	symbolAfter                              // "#,##0.00 ¤", e.g. "1.234,56 €"
+)
+
+// symbolPlacements contains placements of the currency symbol of locales (full tags, then base languages)
+// according to their standard CLDR currency patterns. Locales not listed use symbolBefore.
+// golang.org/x/text does not expose the patterns, so they are listed here.
+var symbolPlacements = map[string]symbolPlacement{
+	"nl": symbolBeforeSpace, "de-AT": symbolBeforeSpace, "de-CH": symbolBeforeSpace, "de-LI": symbolBeforeSpace,
+	"it-CH": symbolBeforeSpace, "pt-BR": symbolBeforeSpace, "pt": symbolBeforeSpace,
+	"be": symbolAfter, "bg": symbolAfter, "ca": symbolAfter, "cs": symbolAfter, "da": symbolAfter, "de": symbolAfter,
+	"el": symbolAfter, "es": symbolAfter, "et": symbolAfter, "eu": symbolAfter, "fi": symbolAfter, "fr": symbolAfter,
+	"gl": symbolAfter, "hr": symbolAfter, "hu": symbolAfter, "is": symbolAfter, "it": symbolAfter, "lt": symbolAfter,
+	"lv": symbolAfter, "nb": symbolAfter, "nn": symbolAfter, "no": symbolAfter, "pl": symbolAfter, "pt-PT": symbolAfter,
+	"ro": symbolAfter, "ru": symbolAfter, "sk": symbolAfter, "sl": symbolAfter, "sr": symbolAfter, "sv": symbolAfter,
+	"uk": symbolAfter, "vi": symbolAfter,
+}
+
+// localeSymbolPlacement returns placement of the currency symbol in the locale, see symbolPlacements.
+func localeSymbolPlacement(tag language.Tag) symbolPlacement {
+	if placement, ok := symbolPlacements[tag.String()]; ok {
+		return placement
+	}
+	base, _ := tag.Base()
+	if region, confidence := tag.Region(); confidence == language.Exact {
+		if placement, ok := symbolPlacements[base.String()+"-"+region.String()]; ok {
+			return placement
+		}
+	}
+	return symbolPlacements[base.String()] // symbolBefore if the language is not listed
+}
+
+// FormatAmount formats amount in minor units of the currency for display in the given locale
+// (a BCP 47 tag, e.g. "en-US" or "de-DE"), with the currency symbol and locale digit grouping and decimal separator,
+// e.g. "$1,234.56" for 123456 USD in "en-US" and "1.234,56 €" for 123456 EUR in "de-DE".
+// The number of decimal places is ISODecimalPlaces of the currency (e.g. none for JPY: "¥1,234");
+// use APIClient.FormatAmount to prefer the value provided by the server.
+// The symbol is placed before or after the number as in the CLDR currency pattern of the locale,
+// separated by a no-break space where the pattern has one or the symbol ends with a letter (e.g. "CHF 1,234.56").
+// Currencies unknown to CLDR are shown with their codes. Invalid locales fall back to the neutral conventions.
+// The amount is converted to floating point, so it is exact for amounts below 2^53 minor units.
+func FormatAmount(amount int, currency, locale string) string {
+	return formatAmount(amount, currency, ISODecimalPlaces(currency), locale)
+}
+
+// FormatAmount is like the FormatAmount function, but the number of decimal places is the value provided
+// by the server if currencies were read by this client before (e.g. by CurrencyDecimalPlaces or CurrencyByCode).
+func (c *APIClient) FormatAmount(amount int, currency, locale string) string {
+	return formatAmount(amount, currency, c.decimalPlaces(currency), locale)
+}
+
+func formatAmount(amount int, currency string, places int, locale string) string {
+	currencyCode := NormalizeCurrency(currency)
+	tag := language.Make(locale)
+	printer := message.NewPrinter(tag)
+
+	value := float64(abs(amount)) / math.Pow10(places)
+	formatted := printer.Sprint(number.Decimal(value, number.Scale(places)))
+
+	symbol := currencyCode
+	if unit, err := xcurrency.ParseISO(currencyCode); err == nil {
+		symbol = printer.Sprint(xcurrency.Symbol(unit))
+	}
+
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+
+	const noBreakSpace = "\u00a0"
+	switch localeSymbolPlacement(tag) {
+	case symbolAfter:
+		return sign + formatted + noBreakSpace + symbol
+	case symbolBeforeSpace:
+		return sign + symbol + noBreakSpace + formatted
+	default:
+		if last, _ := utf8.DecodeLastRuneInString(symbol); unicode.IsLetter(last) {
+			symbol += noBreakSpace
+		}
+		return sign + symbol + formatted
+	}
+}
@@ -0,0 +1,47 @@
+package go_groshi
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatAmount(t *testing.T) {
+	tests := []struct {
+		amount   int
+		currency string
+		locale   string
+		want     string
+	}{
+		{123456, "USD", "en-US", "$1,234.56"},
+		{-123456, "usd", "en-US", "-$1,234.56"},
+		{123456, "EUR", "de-DE", "1.234,56\u00a0€"},
+		{123456, "EUR", "fr-FR", "1\u00a0234,56\u00a0€"},
+		{123456, "EUR", "nl-NL", "€\u00a01.234,56"},
+		{123456, "CHF", "en-US", "CHF\u00a01,234.56"},
+		{1234, "JPY", "en-US", "¥1,234"},
+		{1234, "JPY", "ja-JP", "￥1,234"},
+		{5, "XYZ", "en-US", "XYZ\u00a00.05"},
+		{5, "", "en-US", "0.05"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale+" "+tt.currency, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatAmount(tt.amount, tt.currency, tt.locale))
+		})
+	}
+}
+
+func TestClientFormatAmountUsesServerDecimalPlaces(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, `[{"code": "JPY", "symbol": "¥", "decimal_places": 2}]`)
+	})
+
+	assert.Equal(t, "¥1,234", client.FormatAmount(1234, "JPY", "en-US"))
+
+	_, err := client.CurrencyDecimalPlaces(context.Background(), "JPY")
+	require.NoError(t, err)
+	assert.Equal(t, "¥12.34", client.FormatAmount(1234, "JPY", "en-US"))
+}
@@ -2,10 +2,13 @@ module github.com/groshi-project/go-groshi
 
 go 1.21.0
 
+require (
+	github.com/stretchr/testify v1.8.4
+	golang.org/x/text v0.14.0
+)
+
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/objx v0.5.0 // indirect
-	github.com/stretchr/testify v1.8.4 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
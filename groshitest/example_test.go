@@ -0,0 +1,51 @@
+package groshitest_test
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	go_groshi "github.com/groshi-project/go-groshi"
+	"github.com/groshi-project/go-groshi/groshitest"
+)
+
+func ExampleNewServer() {
+	server := groshitest.NewServer()
+	defer server.Close()
+	client := server.APIClient() // authorized as groshitest.Username
+
+	description := "coffee"
+	timestamp := time.Date(2023, time.May, 1, 9, 0, 0, 0, time.UTC)
+	if _, err := client.TransactionsCreate(-450, "USD", &description, &timestamp); err != nil {
+		panic(err)
+	}
+
+	summary, err := client.TransactionsReadSummary("USD", timestamp.AddDate(0, 0, -1), nil)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(summary.Outcome, summary.TransactionsCount)
+
+	// the fake keeps transactions in memory, so they can be checked directly:
+	for _, transaction := range server.Transactions(groshitest.Username) {
+		fmt.Println(transaction.Description, transaction.Amount)
+	}
+	// Output:
+	// 450 1
+	// coffee -450
+}
+
+func ExampleServer_validateOnly() {
+	server := groshitest.NewServer()
+	defer server.Close()
+	client := server.APIClient()
+
+	input := go_groshi.TransactionInput{Amount: -450, Currency: "USD"}
+	problems, err := client.TransactionsValidate(context.Background(), input)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(len(problems), len(server.Transactions(groshitest.Username)))
+	// Output:
+	// 0 0
+}
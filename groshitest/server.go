@@ -0,0 +1,489 @@
+// Package groshitest provides an in-memory fake of groshi API for testing code which uses go_groshi.APIClient.
+// Typical usage in a test:
+//
+// server := groshitest.NewServer()
+// defer server.Close()
+// client := server.APIClient() // authorized as groshitest.Username
+// transaction, err := client.TransactionsCreate(-500, "USD", nil, nil)
+package groshitest
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	go_groshi "github.com/groshi-project/go-groshi"
+)
+
+// Username and Password are credentials of the user which exists on every new Server.
+const (
+	Username = "groshitest"
+	Password = "groshitest-password"
+)
+
+// tokenLifetime is the lifetime of tokens issued by Server.
+const tokenLifetime = time.Hour
+
+// Currencies are the currencies available on every Server.
+var Currencies = []*go_groshi.Currency{
+	{Code: "EUR", Name: "Euro", Symbol: "€"},
+	{Code: "GBP", Name: "Pound Sterling", Symbol: "£"},
+	{Code: "JPY", Name: "Yen", Symbol: "¥", DecimalPlaces: intPointer(0)},
+	{Code: "USD", Name: "US Dollar", Symbol: "$"},
+}
+
+func intPointer(value int) *int {
+	return &value
+}
+
+// Server is a fake groshi API server keeping users and transactions in memory.
+// It implements authorization, user, transaction, summary and currency endpoints
+// closely enough for testing (including the validate_only param of transaction creation, see TransactionsValidate),
+// but does not convert currencies and ignores unknown query params.
+type Server struct {
+	*httptest.Server
+
+	mutex        sync.Mutex
+	passwords    map[string]string                            // username -> password
+	tokens       map[string]string                            // token -> username
+	transactions map[string]map[string]*go_groshi.Transaction // username -> UUID -> transaction
+}
+
+// NewServer starts and returns a new Server with the user identified by Username and Password.
+// The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		passwords:    map[string]string{Username: Password},
+		tokens:       make(map[string]string),
+		transactions: map[string]map[string]*go_groshi.Transaction{Username: {}},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/auth/login", s.handleAuthLogin)
+	mux.HandleFunc("/auth/refresh", s.authorized(s.handleAuthRefresh))
+	mux.HandleFunc("/user", s.handleUser)
+	mux.HandleFunc("/transactions", s.authorized(s.handleTransactions))
+	mux.HandleFunc("/transactions/summary", s.authorized(s.handleTransactionsSummary))
+	mux.HandleFunc("/transactions/", s.authorized(s.handleTransaction))
+	mux.HandleFunc("/currencies", s.handleCurrencies)
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// APIClient returns a new client of the server authorized as the user identified by Username.
+// The options are applied after the token is set.
+func (s *Server) APIClient(opts ...go_groshi.Option) *go_groshi.APIClient {
+	s.mutex.Lock()
+	token := s.issueToken(Username)
+	s.mutex.Unlock()
+
+	opts = append([]go_groshi.Option{go_groshi.WithToken(token)}, opts...)
+	client, err := go_groshi.NewClient(s.URL, opts...)
+	if err != nil {
+		panic(err) // the URL of httptest.Server is always valid
+	}
+	return client
+}
+
+// Transactions returns all transactions of the user, ordered by timestamp and then by UUID,
+// which is useful for assertions.
+func (s *Server) Transactions(username string) []*go_groshi.Transaction {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.sortedTransactions(username, func(*go_groshi.Transaction) bool { return true })
+}
+
+// issueToken returns a new token of the user. The mutex must be held.
+func (s *Server) issueToken(username string) string {
+	token := randomHex(16)
+	s.tokens[token] = username
+	return token
+}
+
+// randomHex returns a random hex string of n bytes.
+func randomHex(n int) string {
+	data := make([]byte, n)
+	_, _ = rand.Read(data)
+	return fmt.Sprintf("%x", data)
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var uuid [16]byte
+	_, _ = rand.Read(uuid[:])
+	uuid[6] = uuid[6]&0x0f | 0x40
+	uuid[8] = uuid[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16])
+}
+
+// writeJSON writes v as JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, statusCode int, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// writeError writes error response in the format of groshi API.
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, go_groshi.Error{ErrorMessage: message, ErrorDetails: []string{}})
+}
+
+// authorized wraps handler with authorization: the handler is called with the username of the token owner.
+func (s *Server) authorized(handler func(w http.ResponseWriter, r *http.Request, username string)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		username, ok := s.username(r)
+		if !ok {
+			writeError(w, http.StatusUnauthorized, "invalid or missing token")
+			return
+		}
+		handler(w, r, username)
+	}
+}
+
+// username returns the owner of the bearer token of the request.
+func (s *Server) username(r *http.Request) (string, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return "", false
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	username, ok := s.tokens[token]
+	return username, ok
+}
+
+// decodeBody decodes JSON request body into v, writing an error response if it fails.
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid request body")
+		return false
+	}
+	return true
+}
+
+func (s *Server) handleAuthLogin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	var params struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if !decodeBody(w, r, &params) {
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if password, ok := s.passwords[params.Username]; !ok || password != params.Password {
+		writeError(w, http.StatusUnauthorized, "invalid username or password")
+		return
+	}
+	writeJSON(w, http.StatusOK, go_groshi.Authorization{
+		Token:     s.issueToken(params.Username),
+		ExpiresAt: time.Now().Add(tokenLifetime).UTC().Truncate(time.Second),
+	})
+}
+
+func (s *Server) handleAuthRefresh(w http.ResponseWriter, r *http.Request, username string) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	writeJSON(w, http.StatusOK, go_groshi.Authorization{
+		Token:     s.issueToken(username),
+		ExpiresAt: time.Now().Add(tokenLifetime).UTC().Truncate(time.Second),
+	})
+}
+
+func (s *Server) handleUser(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		var params struct {
+			Username string `json:"username"`
+			Password string `json:"password"`
+		}
+		if !decodeBody(w, r, &params) {
+			return
+		}
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		if _, ok := s.passwords[params.Username]; ok {
+			writeError(w, http.StatusConflict, "user with such username already exists")
+			return
+		}
+		s.passwords[params.Username] = params.Password
+		s.transactions[params.Username] = make(map[string]*go_groshi.Transaction)
+		writeJSON(w, http.StatusOK, go_groshi.User{Username: params.Username})
+		return
+	}
+
+	username, ok := s.username(r)
+	if !ok {
+		writeError(w, http.StatusUnauthorized, "invalid or missing token")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, go_groshi.User{Username: username})
+	case http.MethodPut:
+		var params struct {
+			CurrentPassword *string `json:"current_password"`
+			NewUsername     *string `json:"new_username"`
+			NewPassword     *string `json:"new_password"`
+		}
+		if !decodeBody(w, r, &params) {
+			return
+		}
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		if params.CurrentPassword != nil && *params.CurrentPassword != s.passwords[username] {
+			writeError(w, http.StatusForbidden, "current password is wrong")
+			return
+		}
+		if params.NewUsername != nil && *params.NewUsername != username {
+			if _, ok := s.passwords[*params.NewUsername]; ok {
+				writeError(w, http.StatusConflict, "user with such username already exists")
+				return
+			}
+			s.renameUser(username, *params.NewUsername)
+			username = *params.NewUsername
+		}
+		if params.NewPassword != nil {
+			s.passwords[username] = *params.NewPassword
+		}
+		writeJSON(w, http.StatusOK, go_groshi.User{Username: username})
+	case http.MethodDelete:
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		delete(s.passwords, username)
+		delete(s.transactions, username)
+		for token, owner := range s.tokens {
+			if owner == username {
+				delete(s.tokens, token)
+			}
+		}
+		writeJSON(w, http.StatusOK, go_groshi.User{Username: username})
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+// renameUser moves password, tokens and transactions of the user to the new username. The mutex must be held.
+func (s *Server) renameUser(username string, newUsername string) {
+	s.passwords[newUsername] = s.passwords[username]
+	delete(s.passwords, username)
+	s.transactions[newUsername] = s.transactions[username]
+	delete(s.transactions, username)
+	for token, owner := range s.tokens {
+		if owner == username {
+			s.tokens[token] = newUsername
+		}
+	}
+}
+
+// timeRange parses the start_time (required) and end_time (optional) query params.
+func timeRange(r *http.Request) (start time.Time, end *time.Time, err error) {
+	start, err = time.Parse(time.RFC3339Nano, r.URL.Query().Get("start_time"))
+	if err != nil {
+		return time.Time{}, nil, fmt.Errorf("invalid start_time: %w", err)
+	}
+	if value := r.URL.Query().Get("end_time"); value != "" {
+		parsed, err := time.Parse(time.RFC3339Nano, value)
+		if err != nil {
+			return time.Time{}, nil, fmt.Errorf("invalid end_time: %w", err)
+		}
+		end = &parsed
+	}
+	return start, end, nil
+}
+
+// inRange returns a predicate selecting transactions within the range and, if currency is not empty, in the currency.
+func inRange(start time.Time, end *time.Time, currency string) func(*go_groshi.Transaction) bool {
+	return func(transaction *go_groshi.Transaction) bool {
+		if transaction.Timestamp.Before(start) || (end != nil && transaction.Timestamp.After(*end)) {
+			return false
+		}
+		return currency == "" || transaction.Currency == currency
+	}
+}
+
+// sortedTransactions returns transactions of the user satisfying the predicate, ordered by timestamp and UUID.
+// The mutex must be held.
+func (s *Server) sortedTransactions(username string, predicate func(*go_groshi.Transaction) bool) []*go_groshi.Transaction {
+	transactions := make([]*go_groshi.Transaction, 0)
+	for _, transaction := range s.transactions[username] {
+		if predicate(transaction) {
+			copied := *transaction
+			transactions = append(transactions, &copied)
+		}
+	}
+	sort.Slice(transactions, func(i, j int) bool {
+		if !transactions[i].Timestamp.Equal(transactions[j].Timestamp) {
+			return transactions[i].Timestamp.Before(transactions[j].Timestamp)
+		}
+		return transactions[i].UUID < transactions[j].UUID
+	})
+	return transactions
+}
+
+// isCurrencyAvailable reports whether the currency is one of Currencies.
+func isCurrencyAvailable(code string) bool {
+	for _, currency := range Currencies {
+		if currency.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) handleTransactions(w http.ResponseWriter, r *http.Request, username string) {
+	switch r.Method {
+	case http.MethodPost:
+		var params struct {
+			Amount      int        `json:"amount"`
+			Currency    string     `json:"currency"`
+			Description string     `json:"description"`
+			Timestamp   *time.Time `json:"timestamp"`
+		}
+		if !decodeBody(w, r, &params) {
+			return
+		}
+		if !isCurrencyAvailable(params.Currency) {
+			writeError(w, http.StatusBadRequest, "unknown currency")
+			return
+		}
+
+		now := time.Now().UTC().Truncate(time.Second)
+		transaction := &go_groshi.Transaction{
+			UUID:        newUUID(),
+			Amount:      params.Amount,
+			Currency:    params.Currency,
+			Description: params.Description,
+			Timestamp:   now,
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if params.Timestamp != nil {
+			transaction.Timestamp = params.Timestamp.UTC()
+		}
+		if r.URL.Query().Get("validate_only") == "true" {
+			writeJSON(w, http.StatusOK, transaction) // valid, but not created
+			return
+		}
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		s.transactions[username][transaction.UUID] = transaction
+		writeJSON(w, http.StatusOK, transaction)
+	case http.MethodGet:
+		start, end, err := timeRange(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		writeJSON(w, http.StatusOK, s.sortedTransactions(username, inRange(start, end, r.URL.Query().Get("currency"))))
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) handleTransactionsSummary(w http.ResponseWriter, r *http.Request, username string) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	start, end, err := timeRange(r)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	currency := r.URL.Query().Get("currency")
+	if !isCurrencyAvailable(currency) {
+		writeError(w, http.StatusBadRequest, "unknown currency")
+		return
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	summary := go_groshi.TransactionsSummary{Currency: currency}
+	for _, transaction := range s.sortedTransactions(username, inRange(start, end, currency)) {
+		if transaction.Amount > 0 {
+			summary.Income += transaction.Amount
+		} else {
+			summary.Outcome -= transaction.Amount
+		}
+		summary.Total += transaction.Amount
+		summary.TransactionsCount++
+	}
+	writeJSON(w, http.StatusOK, summary)
+}
+
+func (s *Server) handleTransaction(w http.ResponseWriter, r *http.Request, username string) {
+	uuid := strings.TrimPrefix(r.URL.Path, "/transactions/")
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	transaction, ok := s.transactions[username][uuid]
+	if !ok {
+		writeError(w, http.StatusNotFound, "transaction not found")
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, http.StatusOK, transaction)
+	case http.MethodPut:
+		var params struct {
+			NewAmount      *int       `json:"new_amount"`
+			NewCurrency    *string    `json:"new_currency"`
+			NewDescription *string    `json:"new_description"`
+			NewTimestamp   *time.Time `json:"new_timestamp"`
+		}
+		if !decodeBody(w, r, &params) {
+			return
+		}
+		if params.NewCurrency != nil && !isCurrencyAvailable(*params.NewCurrency) {
+			writeError(w, http.StatusBadRequest, "unknown currency")
+			return
+		}
+		if params.NewAmount != nil {
+			transaction.Amount = *params.NewAmount
+		}
+		if params.NewCurrency != nil {
+			transaction.Currency = *params.NewCurrency
+		}
+		if params.NewDescription != nil {
+			transaction.Description = *params.NewDescription
+		}
+		if params.NewTimestamp != nil {
+			transaction.Timestamp = params.NewTimestamp.UTC()
+		}
+		transaction.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+		writeJSON(w, http.StatusOK, transaction)
+	case http.MethodDelete:
+		delete(s.transactions[username], uuid)
+		writeJSON(w, http.StatusOK, transaction)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (s *Server) handleCurrencies(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	writeJSON(w, http.StatusOK, Currencies)
+}
@@ -0,0 +1,50 @@
+package go_groshi
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+)
+
+// DefaultIdempotencyHeader is the default name of the HTTP header used to send idempotency keys.
+const DefaultIdempotencyHeader = "Idempotency-Key"
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a copy of ctx carrying the given idempotency key.
+// Requests sent with the returned context include the key in the idempotency header
+// (Idempotency-Key by default, see SetIdempotencyHeader),
+// which lets the server recognize repeated attempts of the same operation.
+// The key has effect only if the server honors the header.
+//
+// When retries are enabled (see SetRetryConfig), TransactionsCreate and its variants generate a random key
+// for every call made without one, so that all attempts of the call share the key and a server honoring it
+// creates the transaction at most once. Creates carrying a key (generated or explicit) are retried
+// like idempotent requests, although POST is not retried otherwise; note that a server ignoring the header
+// may thus create the transaction more than once if a retried attempt had actually succeeded.
+// Pass an explicit key to deduplicate calls repeated by the application itself.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// idempotencyKeyFromContext returns idempotency key stored in ctx by WithIdempotencyKey.
+func idempotencyKeyFromContext(ctx context.Context) (string, bool) {
+	key, ok := ctx.Value(idempotencyKeyContextKey{}).(string)
+	return key, ok && key != ""
+}
+
+// newIdempotencyKey returns a random (version 4) UUID to be used as an idempotency key.
+func newIdempotencyKey() string {
+	var uuid [16]byte
+	_, _ = rand.Read(uuid[:])     // never fails
+	uuid[6] = uuid[6]&0x0f | 0x40 // version 4
+	uuid[8] = uuid[8]&0x3f | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", uuid[0:4], uuid[4:6], uuid[6:8], uuid[8:10], uuid[10:16])
+}
+
+// SetIdempotencyHeader sets the name of the HTTP header used to send idempotency keys,
+// for gateways expecting a non-standard name such as X-Idempotency-Key.
+// The default is DefaultIdempotencyHeader.
+func (c *APIClient) SetIdempotencyHeader(name string) {
+	c.idempotencyHeader = name
+}
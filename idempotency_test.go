@@ -0,0 +1,30 @@
+package go_groshi
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateIsRetriedWithTheSameIdempotencyKey(t *testing.T) {
+	keys := make([]string, 0)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(DefaultIdempotencyHeader))
+		if len(keys) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(w, `{"uuid": "created"}`)
+	}, WithRetryConfig(RetryConfig{MaxRetries: 1}), WithBackoff(ConstantBackoff{Delay: time.Millisecond}))
+
+	transaction, err := client.TransactionsCreate(-100, "USD", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "created", transaction.UUID)
+
+	require.Len(t, keys, 2)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1])
+}
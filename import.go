@@ -0,0 +1,206 @@
+package go_groshi
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// contentHash returns a stable hash of the transaction input, which is used as its idempotency key.
+func (input TransactionInput) contentHash() string {
+	description := ""
+	if input.Description != nil {
+		description = *input.Description
+	}
+	timestamp := ""
+	if input.Timestamp != nil {
+		timestamp = input.Timestamp.UTC().Format(time.RFC3339Nano)
+	}
+
+	hash := sha256.New()
+	// every field is length-prefixed, so that different inputs can never produce the same byte sequence:
+	for _, field := range []string{fmt.Sprint(input.Amount), input.Currency, timestamp, description} {
+		_, _ = fmt.Fprintf(hash, "%d:%s;", len(field), field)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// ImportTransactions creates transactions from the given inputs in order.
+// Each input is sent with an idempotency key derived from its amount, currency, timestamp and description,
+// so identical inputs (including ones without a timestamp) always get the same key, in any run.
+// Within one call, an input identical to an earlier one is counted as skipped without being sent,
+// even if it is a genuinely repeated purchase.
+// Across runs, deduplication relies entirely on the server: inputs rejected with 409 Conflict
+// (the key was already used) are counted as skipped, while a server replaying the original successful response
+// for a used key makes the input count as created, and a server ignoring the key creates a duplicate.
+// Descriptions exceeding the server's limits (see ServerLimits) are treated according to SetDescriptionPolicy.
+// Import stops at the first other error, returning the counts accumulated so far.
+func (c *APIClient) ImportTransactions(ctx context.Context, inputs []TransactionInput) (created, skipped int, err error) {
+	limits, err := c.ServerLimits(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	seen := make(map[string]struct{}, len(inputs))
+	for _, input := range inputs {
+		key := input.contentHash()
+		if _, ok := seen[key]; ok {
+			skipped++
+			continue
+		}
+		seen[key] = struct{}{}
+
+		description, err := c.enforceDescriptionLimit(input.Description, limits)
+		if err != nil {
+			return created, skipped, err
+		}
+
+		_, err = c.TransactionsCreateContext(
+			WithIdempotencyKey(ctx, key), input.Amount, input.Currency, description, input.Timestamp,
+		)
+		if err != nil {
+			if errors.Is(err, ErrConflict) {
+				skipped++
+				continue
+			}
+			return created, skipped, err
+		}
+		created++
+	}
+	return created, skipped, nil
+}
+
+// ValidateInputsCurrencies returns indices of inputs whose currency is not available on the server,
+// so that they can be fixed before importing. Currency codes are compared after NormalizeCurrency,
+// and empty ones are replaced with the default currency (see SetDefaultCurrency) as TransactionsCreate does.
+// The list of available currencies is requested once and cached by the client.
+func (c *APIClient) ValidateInputsCurrencies(ctx context.Context, inputs []TransactionInput) ([]int, error) {
+	codes, err := c.currencyCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	invalid := make([]int, 0)
+	for i, input := range inputs {
+		if _, ok := codes[NormalizeCurrency(c.currencyOrDefault(input.Currency))]; !ok {
+			invalid = append(invalid, i)
+		}
+	}
+	return invalid, nil
+}
+
+// VerifyImported checks that transactions with the given UUIDs exist on the server
+// and returns UUIDs of those which do not (in the order they were given).
+// Transactions are read concurrently; a 404 Not Found response marks a transaction as missing,
+// while any other error aborts the verification.
+func (c *APIClient) VerifyImported(ctx context.Context, uuids []string) (missing []string, err error) {
+	isMissing := make([]bool, len(uuids))
+	err = forEachConcurrently(ctx, len(uuids), c.concurrency, func(ctx context.Context, i int) error {
+		_, err := c.TransactionsReadOneContext(ctx, uuids[i], nil)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				isMissing[i] = true
+				return nil
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	missing = make([]string, 0)
+	for i, uuid := range uuids {
+		if isMissing[i] {
+			missing = append(missing, uuid)
+		}
+	}
+	return missing, nil
+}
+
+// TransactionsCreateMany creates transactions from the given inputs, sending up to SetConcurrency requests in parallel
+// (groshi API has no batch endpoint). Both returned slices are index-aligned with inputs:
+// for every input, either the created transaction or the error is set, so a failure of one input
+// does not prevent others from being created. Inputs not attempted because the context was done get its error.
+// Descriptions exceeding the server's limits (see ServerLimits) are treated according to SetDescriptionPolicy.
+func (c *APIClient) TransactionsCreateMany(inputs []TransactionInput) ([]*Transaction, []error) {
+	return c.TransactionsCreateManyContext(context.Background(), inputs)
+}
+
+// TransactionsCreateManyContext is like TransactionsCreateMany but uses the given context for the requests.
+func (c *APIClient) TransactionsCreateManyContext(ctx context.Context, inputs []TransactionInput) ([]*Transaction, []error) {
+	transactions := make([]*Transaction, len(inputs))
+	errs := make([]error, len(inputs))
+	attempted := make([]bool, len(inputs))
+
+	limits, err := c.ServerLimits(ctx)
+	if err != nil {
+		for i := range inputs {
+			errs[i] = err
+		}
+		return transactions, errs
+	}
+
+	_ = forEachConcurrently(ctx, len(inputs), c.concurrency, func(ctx context.Context, i int) error {
+		attempted[i] = true
+		input := inputs[i]
+		description, err := c.enforceDescriptionLimit(input.Description, limits)
+		if err != nil {
+			errs[i] = err
+			return nil
+		}
+		transactions[i], errs[i] = c.TransactionsCreateContext(ctx, input.Amount, input.Currency, description, input.Timestamp)
+		return nil // errors are reported per input
+	})
+
+	for i := range inputs {
+		if !attempted[i] {
+			errs[i] = ctx.Err()
+		}
+	}
+	return transactions, errs
+}
+
+// TransactionsDeleteMany deletes transactions with the given UUIDs, sending up to SetConcurrency requests in parallel
+// (groshi API has no batch endpoint). It returns errors of failed deletions keyed by UUID; successfully deleted
+// transactions are not included, so the map is empty if all deletions succeeded. A failure of one deletion
+// (e.g. 404 Not Found) does not prevent others from being attempted. Repeated UUIDs are deleted once.
+func (c *APIClient) TransactionsDeleteMany(uuids []string) map[string]error {
+	return c.TransactionsDeleteManyContext(context.Background(), uuids)
+}
+
+// TransactionsDeleteManyContext is like TransactionsDeleteMany but uses the given context for the requests.
+// Deletions not attempted because the context was done get its error.
+func (c *APIClient) TransactionsDeleteManyContext(ctx context.Context, uuids []string) map[string]error {
+	unique := make([]string, 0, len(uuids))
+	seen := make(map[string]struct{}, len(uuids))
+	for _, uuid := range uuids {
+		if _, ok := seen[uuid]; !ok {
+			seen[uuid] = struct{}{}
+			unique = append(unique, uuid)
+		}
+	}
+
+	errs := make([]error, len(unique))
+	attempted := make([]bool, len(unique))
+	_ = forEachConcurrently(ctx, len(unique), c.concurrency, func(ctx context.Context, i int) error {
+		attempted[i] = true
+		_, errs[i] = c.TransactionsDeleteContext(ctx, unique[i])
+		return nil // errors are reported per UUID
+	})
+
+	failed := make(map[string]error)
+	for i, uuid := range unique {
+		if !attempted[i] {
+			errs[i] = ctx.Err()
+		}
+		if errs[i] != nil {
+			failed[uuid] = errs[i]
+		}
+	}
+	return failed
+}
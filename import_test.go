@@ -0,0 +1,58 @@
+package go_groshi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyImportedCancelledContext(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	uuids := []string{"a", "b", "c", "d", "e", "f"}
+	for run := 0; run < 100; run++ {
+		missing, err := client.VerifyImported(ctx, uuids)
+		require.ErrorIs(t, err, context.Canceled)
+		assert.Nil(t, missing)
+	}
+}
+
+func TestImportsUseDefaultCurrency(t *testing.T) {
+	var createdCurrencies []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/currencies":
+			writeJSON(w, `[{"code": "USD", "symbol": "$"}]`)
+		case "/limits":
+			http.NotFound(w, r)
+		default:
+			var body transactionCreateRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			createdCurrencies = append(createdCurrencies, body.Currency)
+			writeJSON(w, `{"uuid": "created"}`)
+		}
+	}, WithDefaultCurrency("usd"), WithConcurrency(1))
+
+	invalid, err := client.ValidateInputsCurrencies(context.Background(), []TransactionInput{
+		{Amount: -100, Currency: ""}, {Amount: -100, Currency: "EUR"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{1}, invalid)
+
+	csv := "timestamp,amount,currency,description\n,-100,,coffee\n"
+	results, err := client.TransactionsImportCSV(context.Background(), strings.NewReader(csv), true)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, []string{"USD"}, createdCurrencies)
+}
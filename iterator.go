@@ -0,0 +1,167 @@
+package go_groshi
+
+import (
+	"context"
+	"time"
+)
+
+// iteratorWindow is the length of the time range ("page") of transactions TransactionsIterator reads at once.
+const iteratorWindow = 30 * 24 * time.Hour
+
+// minIteratorWindow is the length below which pages of TransactionsIterator are not shrunk, see readPage.
+const minIteratorWindow = time.Second
+
+// TransactionsIterator iterates over transactions in a time range, reading them lazily page by page,
+// where each page contains transactions of a 30-day window (or a shorter one if the window contains more transactions
+// than MaxPageSize of ServerLimits, which are requested once). Within the range, transactions are yielded
+// ordered by timestamp and then by UUID. Typical usage:
+//
+// iterator := client.TransactionsIterate(startTime, endTime)
+// for iterator.Next() { fmt.Println(iterator.Transaction().Description) }
+// err := iterator.Err() // check for errors after the loop
+type TransactionsIterator struct {
+	client *APIClient
+	ctx    context.Context
+
+	nextStart time.Time // start of the next page
+	endTime   time.Time
+	window    time.Duration // length of the time range of a page
+	done      bool          // the last page was read
+
+	maxPageSize int // MaxPageSize of the server's limits, zero until the first page is read
+
+	page     []*Transaction
+	previous map[string]struct{} // UUIDs of the previous page, to skip duplicates at page boundaries
+	current  *Transaction
+	err      error
+}
+
+// TransactionsIterate returns iterator over transactions between startTime and endTime.
+// Unlike TransactionsReadMany, it never holds more than one page of transactions in memory.
+func (c *APIClient) TransactionsIterate(startTime time.Time, endTime time.Time) *TransactionsIterator {
+	return c.TransactionsIterateContext(context.Background(), startTime, endTime)
+}
+
+// TransactionsIterateContext is like TransactionsIterate but uses the given context for all requests of the iterator.
+func (c *APIClient) TransactionsIterateContext(ctx context.Context, startTime time.Time, endTime time.Time) *TransactionsIterator {
+	return &TransactionsIterator{
+		client:    c,
+		ctx:       ctx,
+		nextStart: startTime,
+		endTime:   endTime,
+		window:    iteratorWindow,
+		done:      startTime.After(endTime),
+	}
+}
+
+// Next advances the iterator to the next transaction, reading the next page if needed.
+// It returns false when there are no more transactions or an error occurred (see Err).
+func (it *TransactionsIterator) Next() bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			it.current = nil
+			return false
+		}
+		it.readPage()
+	}
+
+	it.current = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// readPage reads transactions of the next time window into the page.
+// If the window contains at least MaxPageSize transactions (see ServerLimits), the server may have returned
+// only a part of them, so the window is halved (down to minIteratorWindow) and read again;
+// the following pages keep the reduced window.
+func (it *TransactionsIterator) readPage() {
+	if it.maxPageSize == 0 {
+		limits, err := it.client.ServerLimits(it.ctx)
+		if err != nil {
+			it.err = err
+			return
+		}
+		it.maxPageSize = limits.MaxPageSize
+	}
+
+	var transactions []*Transaction
+	var windowEnd time.Time
+	for {
+		windowEnd = it.nextStart.Add(it.window)
+		queryEnd := it.client.periodEnd(windowEnd)
+		last := !windowEnd.Before(it.endTime)
+		if last {
+			queryEnd = it.endTime
+		}
+
+		var err error
+		// pages are not limited by SetMaxResults, which TransactionsReadManyAll applies to the total count:
+		transactions, err = it.client.readTransactions(it.ctx, it.nextStart, &queryEnd, nil, TransactionsFilter{}, 0)
+		if err != nil {
+			it.err = err
+			return
+		}
+		if len(transactions) >= it.maxPageSize && it.window/2 >= minIteratorWindow {
+			it.window /= 2
+			continue
+		}
+		it.done = last
+		break
+	}
+	sortTransactions(transactions)
+
+	current := make(map[string]struct{}, len(transactions))
+	page := make([]*Transaction, 0, len(transactions))
+	for _, transaction := range dedupTransactions(transactions) {
+		current[transaction.UUID] = struct{}{}
+		if _, ok := it.previous[transaction.UUID]; !ok {
+			page = append(page, transaction)
+		}
+	}
+
+	it.page = page
+	it.previous = current
+	it.nextStart = windowEnd
+}
+
+// Transaction returns the current transaction. It must be called only after Next returned true.
+func (it *TransactionsIterator) Transaction() *Transaction {
+	return it.current
+}
+
+// Err returns the error which stopped the iteration, or nil if the iteration completed (or is in progress) normally.
+func (it *TransactionsIterator) Err() error {
+	return it.err
+}
+
+// allTransactionsWindow is the length of the page read by TransactionsReadManyAll:
+// pages are larger than the ones of TransactionsIterate, since most of them are expected to be empty.
+const allTransactionsWindow = 365 * 24 * time.Hour
+
+// TransactionsReadManyAll returns all transactions of the user, ordered by timestamp and then by UUID,
+// without requiring a time range. It reads transactions with timestamps from the Unix epoch to a year ahead
+// using TransactionsIterate with year-long pages, so it sends dozens of requests even for an empty account,
+// and holds all transactions in memory: it is intended for small personal accounts,
+// prefer TransactionsIterate for large ones. ErrResultSetTooLarge is returned if the total number
+// of transactions exceeds the limit set by SetMaxResults.
+func (c *APIClient) TransactionsReadManyAll() ([]*Transaction, error) {
+	return c.TransactionsReadManyAllContext(context.Background())
+}
+
+// TransactionsReadManyAllContext is like TransactionsReadManyAll but uses the given context for the requests.
+func (c *APIClient) TransactionsReadManyAllContext(ctx context.Context) ([]*Transaction, error) {
+	iterator := c.TransactionsIterateContext(ctx, time.Unix(0, 0).UTC(), time.Now().AddDate(1, 0, 0))
+	iterator.window = allTransactionsWindow
+
+	transactions := make([]*Transaction, 0)
+	for iterator.Next() {
+		transactions = append(transactions, iterator.Transaction())
+		if err := c.checkResultsCount(len(transactions)); err != nil {
+			return nil, err
+		}
+	}
+	if err := iterator.Err(); err != nil {
+		return nil, err
+	}
+	return transactions, nil
+}
@@ -0,0 +1,96 @@
+package go_groshi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"unicode/utf8"
+)
+
+// DefaultLimits are conservative limits assumed when the server does not publish its own.
+var DefaultLimits = Limits{
+	MaxPageSize:          100,
+	MaxDescriptionLength: 255,
+}
+
+// ErrDescriptionTooLong is returned when a transaction description exceeds
+// the server's MaxDescriptionLength and DescriptionReject policy is used.
+var ErrDescriptionTooLong = errors.New("description is too long")
+
+// DescriptionPolicy determines how bulk helpers treat descriptions exceeding the server's limit.
+type DescriptionPolicy int
+
+const (
+	DescriptionReject   DescriptionPolicy = iota // return ErrDescriptionTooLong
+	DescriptionTruncate                          // truncate description to the maximum length
+)
+
+// SetDescriptionPolicy sets how bulk helpers (ImportTransactions, TransactionsCreateMany
+// and TransactionsImportCSV) treat descriptions
+// exceeding MaxDescriptionLength returned by ServerLimits. The default is DescriptionReject.
+func (c *APIClient) SetDescriptionPolicy(policy DescriptionPolicy) {
+	c.descriptionPolicy = policy
+}
+
+// ServerLimits returns limits published by the server.
+// If the server does not publish limits (responds to the request with 404 Not Found or 405 Method Not Allowed),
+// DefaultLimits are returned; limits missing in the server's response are taken from DefaultLimits too.
+// The limits are requested once and then cached for the whole lifetime of the client.
+func (c *APIClient) ServerLimits(ctx context.Context) (*Limits, error) {
+	c.limitsMutex.Lock()
+	defer c.limitsMutex.Unlock()
+
+	if c.limits != nil {
+		limits := *c.limits
+		return &limits, nil
+	}
+
+	limits := Limits{}
+	err := c.sendRequest(
+		ctx,
+		http.MethodGet,
+		"/limits",
+		nil,
+		nil,
+		false,
+		&limits,
+	)
+	if err != nil {
+		var apiErr APIError
+		if !errors.As(err, &apiErr) ||
+			(apiErr.HTTPStatusCode != http.StatusNotFound && apiErr.HTTPStatusCode != http.StatusMethodNotAllowed) {
+			return nil, err
+		}
+		limits = DefaultLimits
+	}
+
+	if limits.MaxPageSize <= 0 {
+		limits.MaxPageSize = DefaultLimits.MaxPageSize
+	}
+	if limits.MaxDescriptionLength <= 0 {
+		limits.MaxDescriptionLength = DefaultLimits.MaxDescriptionLength
+	}
+
+	c.limits = &limits
+	result := limits
+	return &result, nil
+}
+
+// enforceDescriptionLimit checks description against MaxDescriptionLength of the limits
+// and truncates or rejects it according to the client's description policy.
+func (c *APIClient) enforceDescriptionLimit(description *string, limits *Limits) (*string, error) {
+	if description == nil || utf8.RuneCountInString(*description) <= limits.MaxDescriptionLength {
+		return description, nil
+	}
+
+	if c.descriptionPolicy != DescriptionTruncate {
+		return nil, fmt.Errorf(
+			"%w: %v characters, maximum is %v",
+			ErrDescriptionTooLong, utf8.RuneCountInString(*description), limits.MaxDescriptionLength,
+		)
+	}
+	truncated := []rune(*description)[:limits.MaxDescriptionLength]
+	result := string(truncated)
+	return &result, nil
+}
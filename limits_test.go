@@ -0,0 +1,124 @@
+package go_groshi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newLimitedServerClient returns a client of a server publishing the given limits
+// and returning at most limits.MaxPageSize of the transactions per read.
+func newLimitedServerClient(t *testing.T, limits Limits, transactions []*Transaction) *APIClient {
+	return newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/limits":
+			require.NoError(t, json.NewEncoder(w).Encode(limits))
+		case "/transactions":
+			query := r.URL.Query()
+			startTime, err := time.Parse(time.RFC3339, query.Get("start_time"))
+			require.NoError(t, err)
+			endTime, err := time.Parse(time.RFC3339, query.Get("end_time"))
+			require.NoError(t, err)
+
+			page := make([]*Transaction, 0)
+			for _, transaction := range transactions {
+				if !transaction.Timestamp.Before(startTime) && !transaction.Timestamp.After(endTime) && len(page) < limits.MaxPageSize {
+					page = append(page, transaction)
+				}
+			}
+			require.NoError(t, json.NewEncoder(w).Encode(page))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func TestIteratorRespectsMaxPageSize(t *testing.T) {
+	startTime := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	transactions := make([]*Transaction, 0)
+	for i := 0; i < 7; i++ {
+		transactions = append(transactions, &Transaction{
+			UUID:      string(rune('a' + i)),
+			Amount:    100,
+			Currency:  "USD",
+			Timestamp: startTime.Add(time.Duration(i) * 24 * time.Hour),
+		})
+	}
+	client := newLimitedServerClient(t, Limits{MaxPageSize: 2, MaxDescriptionLength: 255}, transactions)
+
+	iterator := client.TransactionsIterate(startTime, startTime.AddDate(0, 2, 0))
+	uuids := make([]string, 0)
+	for iterator.Next() {
+		uuids = append(uuids, iterator.Transaction().UUID)
+	}
+	require.NoError(t, iterator.Err())
+	assert.Equal(t, []string{"a", "b", "c", "d", "e", "f", "g"}, uuids)
+}
+
+func TestCreateManyRespectsMaxDescriptionLength(t *testing.T) {
+	var descriptions []string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/limits":
+			writeJSON(w, `{"max_page_size": 100, "max_description_length": 5}`)
+			return
+		case "/currencies":
+			writeJSON(w, `[{"code": "USD", "symbol": "$"}]`)
+			return
+		}
+		var body transactionCreateRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		descriptions = append(descriptions, *body.Description)
+		writeJSON(w, `{"uuid": "created"}`)
+	}, WithConcurrency(1))
+
+	long := "groceries"
+	inputs := []TransactionInput{{Amount: -100, Currency: "USD", Description: &long}}
+
+	_, errs := client.TransactionsCreateManyContext(context.Background(), inputs)
+	assert.ErrorIs(t, errs[0], ErrDescriptionTooLong)
+	assert.Empty(t, descriptions)
+
+	client.SetDescriptionPolicy(DescriptionTruncate)
+	_, errs = client.TransactionsCreateManyContext(context.Background(), inputs)
+	require.NoError(t, errs[0])
+
+	results, err := client.TransactionsImportCSV(context.Background(), strings.NewReader(
+		"timestamp,amount,currency,description\n,-100,USD,groceries\n",
+	), false)
+	require.NoError(t, err)
+	require.NoError(t, results[0].Err)
+	assert.Equal(t, []string{"groce", "groce"}, descriptions)
+}
+
+func TestIteratorIsNotLimitedByMaxResults(t *testing.T) {
+	startTime := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	transactions := make([]*Transaction, 0)
+	for i := 0; i < 5; i++ {
+		transactions = append(transactions, &Transaction{
+			UUID:      string(rune('a' + i)),
+			Amount:    100,
+			Currency:  "USD",
+			Timestamp: startTime.Add(time.Duration(i) * time.Hour), // all in the first page
+		})
+	}
+	client := newLimitedServerClient(t, Limits{MaxPageSize: 3, MaxDescriptionLength: 255}, transactions)
+	client.SetMaxResults(2)
+
+	iterator := client.TransactionsIterate(startTime, startTime.AddDate(0, 2, 0))
+	uuids := make([]string, 0)
+	for iterator.Next() {
+		uuids = append(uuids, iterator.Transaction().UUID)
+	}
+	require.NoError(t, iterator.Err())
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, uuids)
+
+	_, err := client.TransactionsReadManyAll()
+	assert.ErrorIs(t, err, ErrResultSetTooLarge)
+}
@@ -22,6 +22,8 @@ type Transaction struct {
 	Description string    `json:"description"`
 	Timestamp   time.Time `json:"timestamp"`
 
+	Tags []string `json:"tags,omitempty"` // tags (categories) of the transaction, nil if the server does not support them
+
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
@@ -35,12 +37,25 @@ type TransactionsSummary struct {
 	Total   int `json:"total"`
 
 	TransactionsCount int `json:"transactions_count"`
+
+	// Truncated is true if the server computed the summary over only a part of the transactions
+	// (e.g. because the range is too large). It is false if the server does not report it.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// IsPartial reports whether the summary is computed over only a part of the transactions,
+// so its totals and count must not be presented as exact. See Truncated.
+func (s *TransactionsSummary) IsPartial() bool {
+	return s.Truncated
 }
 
-// Currency represents currency code along with its respective symbol.
+// Currency represents currency code along with its respective name and symbol.
+// Name is empty and DecimalPlaces is nil if the server does not provide them.
 type Currency struct {
-	Code   string `json:"code"`
-	Symbol string `json:"symbol"`
+	Code          string `json:"code"`
+	Name          string `json:"name,omitempty"`
+	Symbol        string `json:"symbol"`
+	DecimalPlaces *int   `json:"decimal_places,omitempty"`
 }
 
 // Error represents response containing information about API error.
@@ -48,3 +63,17 @@ type Error struct {
 	ErrorMessage string   `json:"error_message"`
 	ErrorDetails []string `json:"error_details"`
 }
+
+// TransactionInput represents parameters of a new transaction, used by bulk helpers.
+type TransactionInput struct {
+	Amount      int
+	Currency    string
+	Description *string
+	Timestamp   *time.Time
+}
+
+// Limits represents limits published by groshi server.
+type Limits struct {
+	MaxPageSize          int `json:"max_page_size"`          // maximum number of transactions returned by a single read
+	MaxDescriptionLength int `json:"max_description_length"` // maximum length of transaction description in characters
+}
@@ -0,0 +1,132 @@
+package go_groshi
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ErrCurrencyMismatch is returned by Money arithmetic when amounts are in different currencies.
+var ErrCurrencyMismatch = errors.New("currencies do not match")
+
+// Money represents an amount of money in minor units (e.g. cents) of the currency.
+// Number of decimal places of the currency is DecimalPlaces if it is set (e.g. to the value returned
+// by APIClient.CurrencyDecimalPlaces), otherwise it is taken from the built-in ISO 4217 table (see ISODecimalPlaces).
+type Money struct {
+	Amount        int    // amount in minor units, e.g. 1234 for 12.34 USD
+	Currency      string // currency code, e.g. "USD"
+	DecimalPlaces *int   // number of decimal places of the currency, nil means ISODecimalPlaces
+}
+
+// NewMoney returns Money with the given amount in minor units and normalized currency code.
+func NewMoney(amount int, currency string) Money {
+	return Money{Amount: amount, Currency: NormalizeCurrency(currency)}
+}
+
+// String returns the amount in major units followed by the currency code, e.g. "-12.34 USD".
+func (m Money) String() string {
+	return fmt.Sprintf("%v %v", formatMinorUnits(m.Amount, m.decimalPlaces()), m.Currency)
+}
+
+// Float64 returns the amount in major units, e.g. 12.34 for 1234 minor units of USD.
+// Floating point values are inexact and should be used only for display or statistics.
+func (m Money) Float64() float64 {
+	return float64(m.Amount) / math.Pow10(m.decimalPlaces())
+}
+
+// decimalPlaces returns number of decimal places of the currency, see Money.
+func (m Money) decimalPlaces() int {
+	return currencyDecimalPlaces(&Currency{Code: m.Currency, DecimalPlaces: m.DecimalPlaces})
+}
+
+// Add returns sum of the amounts, or ErrCurrencyMismatch if they are in different currencies.
+func (m Money) Add(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency, DecimalPlaces: m.DecimalPlaces}, nil
+}
+
+// Sub returns difference of the amounts, or ErrCurrencyMismatch if they are in different currencies.
+func (m Money) Sub(other Money) (Money, error) {
+	if err := m.checkCurrency(other); err != nil {
+		return Money{}, err
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency, DecimalPlaces: m.DecimalPlaces}, nil
+}
+
+// checkCurrency returns ErrCurrencyMismatch if other is in a different currency.
+func (m Money) checkCurrency(other Money) error {
+	if NormalizeCurrency(m.Currency) != NormalizeCurrency(other.Currency) {
+		return fmt.Errorf("%w: %v and %v", ErrCurrencyMismatch, m.Currency, other.Currency)
+	}
+	return nil
+}
+
+// formatMinorUnits formats amount in minor units as a decimal number with the given number of decimal places.
+func formatMinorUnits(amount int, places int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+	digits := fmt.Sprint(abs(amount))
+	if places <= 0 {
+		return sign + digits
+	}
+
+	if len(digits) <= places {
+		digits = strings.Repeat("0", places-len(digits)+1) + digits
+	}
+	return sign + digits[:len(digits)-places] + "." + digits[len(digits)-places:]
+}
+
+// MoneyAmount returns amount of the transaction as Money.
+func (t *Transaction) MoneyAmount() Money {
+	return NewMoney(t.Amount, t.Currency)
+}
+
+// ErrInvalidAmount is returned by ParseAmount when the amount is not a valid decimal number for the currency.
+var ErrInvalidAmount = errors.New("invalid amount")
+
+// ParseAmount converts decimal amount in major units (e.g. "12.34" or "-5") to minor units of the currency
+// (1234 and -500 for USD). Inputs with more fractional digits than the currency has decimal places
+// are rejected rather than silently rounded. Number of decimal places is currency.DecimalPlaces if it is set
+// (e.g. by APIClient.CurrencyByCode), otherwise ISODecimalPlaces of currency.Code.
+func ParseAmount(amount string, currency *Currency) (int, error) {
+	return parseAmount(amount, currencyDecimalPlaces(currency))
+}
+
+func parseAmount(amount string, places int) (int, error) {
+	value := strings.TrimSpace(amount)
+	sign := ""
+	if strings.HasPrefix(value, "-") || strings.HasPrefix(value, "+") {
+		sign, value = value[:1], value[1:]
+	}
+
+	integerPart, fractionalPart, hasPoint := strings.Cut(value, ".")
+	if integerPart == "" || (hasPoint && fractionalPart == "") || !isDigits(integerPart) || !isDigits(fractionalPart) {
+		return 0, fmt.Errorf("%w: %q is not a decimal number", ErrInvalidAmount, amount)
+	}
+	if len(fractionalPart) > places {
+		return 0, fmt.Errorf("%w: %q has more than %v decimal places", ErrInvalidAmount, amount, places)
+	}
+
+	fractionalPart += strings.Repeat("0", places-len(fractionalPart))
+	minorUnits, err := strconv.Atoi(sign + integerPart + fractionalPart)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q is out of range", ErrInvalidAmount, amount)
+	}
+	return minorUnits, nil
+}
+
+// isDigits reports whether s consists of ASCII digits only.
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
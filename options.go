@@ -0,0 +1,203 @@
+package go_groshi
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Option configures APIClient created by NewClient.
+// Every option has the same effect as the respective setter method of APIClient.
+type Option func(c *APIClient)
+
+// WithToken sets the authorization token, see SetToken.
+func WithToken(token string) Option {
+	return func(c *APIClient) {
+		c.SetToken(token)
+	}
+}
+
+// WithTimeout sets the time limit for every request, see SetTimeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *APIClient) {
+		c.SetTimeout(timeout)
+	}
+}
+
+// WithOperationTimeout sets the time limit for requests of the given category of operations,
+// overriding the one set by WithTimeout for them, see SetOperationTimeout.
+func WithOperationTimeout(category string, timeout time.Duration) Option {
+	return func(c *APIClient) {
+		c.SetOperationTimeout(category, timeout)
+	}
+}
+
+// WithNoTimeout disables the client-level timeout, so that requests are limited only by their contexts,
+// e.g. for long exports from a slow server. It is the same as WithTimeout(0). Note that the Timeout
+// of an HTTP client set with WithHTTPClient still applies; the client's own HTTP client has no timeout.
+func WithNoTimeout() Option {
+	return func(c *APIClient) {
+		c.SetTimeout(0)
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests, see SetHTTPClient.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *APIClient) {
+		c.SetHTTPClient(httpClient)
+	}
+}
+
+// WithTransport wraps the transport of the HTTP client with the given RoundTripper wrappers,
+// e.g. for tracing, metrics or logging of HTTP calls. Wrappers are composed in order, so that
+// the first one is the outermost and sees every request first. The innermost transport is the one of
+// the client's own HTTP client (a clone of http.DefaultTransport) or, if WithHTTPClient is given before
+// this option, the transport of that client (http.DefaultTransport if it is nil); the given client is not modified.
+func WithTransport(wrappers ...func(http.RoundTripper) http.RoundTripper) Option {
+	return func(c *APIClient) {
+		httpClient := *c.httpClient
+		transport := httpClient.Transport
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		for i := len(wrappers) - 1; i >= 0; i-- {
+			transport = wrappers[i](transport)
+		}
+		httpClient.Transport = transport
+		c.httpClient = &httpClient
+	}
+}
+
+// WithMinTLSVersion sets the minimum accepted TLS version, see SetMinTLSVersion.
+func WithMinTLSVersion(v uint16) Option {
+	return func(c *APIClient) {
+		c.SetMinTLSVersion(v)
+	}
+}
+
+// WithRetryConfig sets configuration of retrying failed requests, see SetRetryConfig.
+func WithRetryConfig(config RetryConfig) Option {
+	return func(c *APIClient) {
+		c.SetRetryConfig(config)
+	}
+}
+
+// WithBackoff sets the strategy of waiting between retries, see SetBackoff.
+func WithBackoff(backoff Backoff) Option {
+	return func(c *APIClient) {
+		c.SetBackoff(backoff)
+	}
+}
+
+// WithAutoRefresh enables or disables automatic token refresh, see SetAutoRefresh.
+func WithAutoRefresh(autoRefresh bool) Option {
+	return func(c *APIClient) {
+		c.SetAutoRefresh(autoRefresh)
+	}
+}
+
+// WithReadOnly enables or disables read-only mode, see SetReadOnly.
+func WithReadOnly(readOnly bool) Option {
+	return func(c *APIClient) {
+		c.SetReadOnly(readOnly)
+	}
+}
+
+// WithConcurrency sets the maximum number of parallel requests of fan-out helpers, see SetConcurrency.
+func WithConcurrency(n int) Option {
+	return func(c *APIClient) {
+		c.SetConcurrency(n)
+	}
+}
+
+// WithResponseInspector sets a function called with every HTTP response, see SetResponseInspector.
+func WithResponseInspector(inspector func(response *http.Response)) Option {
+	return func(c *APIClient) {
+		c.SetResponseInspector(inspector)
+	}
+}
+
+// WithUserAgent appends product (e.g. "my-app/1.2.3") to the User-Agent header sent with every request.
+// The header starts with "go-groshi/<version>", and every WithUserAgent option appends to it,
+// so that several layers of an application can identify themselves, e.g. "go-groshi/0.1.0 my-lib/2.0 my-app/1.2.3".
+func WithUserAgent(product string) Option {
+	return func(c *APIClient) {
+		if product = strings.TrimSpace(product); product != "" {
+			c.userAgent += " " + product
+		}
+	}
+}
+
+// WithContext sets the base context of the client, e.g. for graceful shutdown:
+// once ctx is done, in-flight requests are aborted and all methods return its error without sending any requests.
+// Contexts passed to the methods are still respected, a request is aborted when either of contexts is done.
+func WithContext(ctx context.Context) Option {
+	return func(c *APIClient) {
+		c.baseCtx = ctx
+	}
+}
+
+// WithTimeFormat sets the layout of timestamps sent to groshi API, see SetTimeFormat.
+func WithTimeFormat(layout string) Option {
+	return func(c *APIClient) {
+		c.SetTimeFormat(layout)
+	}
+}
+
+// WithDefaultCurrency sets the currency used when an empty currency is passed to methods, see SetDefaultCurrency.
+func WithDefaultCurrency(code string) Option {
+	return func(c *APIClient) {
+		c.SetDefaultCurrency(code)
+	}
+}
+
+// WithMaxResponseBytes limits the size of response bodies, see SetMaxResponseBytes.
+func WithMaxResponseBytes(n int64) Option {
+	return func(c *APIClient) {
+		c.SetMaxResponseBytes(n)
+	}
+}
+
+// WithRequestCompression enables gzip compression of request bodies, see SetRequestCompression.
+func WithRequestCompression() Option {
+	return func(c *APIClient) {
+		c.SetRequestCompression(true)
+	}
+}
+
+// WithLocation sets the location which timestamps of responses are converted to, see SetLocation.
+func WithLocation(loc *time.Location) Option {
+	return func(c *APIClient) {
+		c.SetLocation(loc)
+	}
+}
+
+// WithAPIVersion pins the client to the given version of groshi API, see SetAPIVersion.
+func WithAPIVersion(v string) Option {
+	return func(c *APIClient) {
+		c.SetAPIVersion(v)
+	}
+}
+
+// WithDestructiveOperations enables or disables destructive operations, such as UserDelete,
+// see SetDestructiveOperations.
+func WithDestructiveOperations(enabled bool) Option {
+	return func(c *APIClient) {
+		c.SetDestructiveOperations(enabled)
+	}
+}
+
+// Logger is called after every request sent to groshi API with its HTTP method, URL (including query params),
+// HTTP status code of the response and duration of the request (including retries).
+// If the request failed, err is the returned error, and status is 0 unless a response was received;
+// calls failed before sending anything (e.g. with ErrNoToken or ErrClosed) are reported too.
+// Request headers, including Authorization, are never passed to the logger.
+type Logger func(method string, url string, status int, duration time.Duration, err error)
+
+// WithLogger sets a function called after every request, e.g. for debug logging.
+func WithLogger(logger Logger) Option {
+	return func(c *APIClient) {
+		c.logger = logger
+	}
+}
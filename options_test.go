@@ -0,0 +1,71 @@
+package go_groshi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// loggedRequest is a call of Logger.
+type loggedRequest struct {
+	method string
+	url    string
+	status int
+	err    error
+}
+
+func TestLoggerIsCalledOnErrorPaths(t *testing.T) {
+	var logged []loggedRequest
+	logger := func(method string, url string, status int, duration time.Duration, err error) {
+		logged = append(logged, loggedRequest{method, url, status, err})
+	}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}, WithLogger(logger))
+	baseURL := client.baseURL
+
+	_, err := client.UserRead()
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	client.SetToken("")
+	_, err = client.UserRead()
+	assert.ErrorIs(t, err, ErrNoToken)
+
+	require.NoError(t, client.Close())
+	_, err = client.UserRead()
+	assert.ErrorIs(t, err, ErrClosed)
+
+	require.Len(t, logged, 3)
+	assert.Equal(t, loggedRequest{http.MethodGet, baseURL + "/user", http.StatusNotFound, logged[0].err}, logged[0])
+	for _, request := range logged[1:] {
+		assert.Equal(t, baseURL+"/user", request.url)
+		assert.Zero(t, request.status)
+	}
+	assert.ErrorIs(t, logged[1].err, ErrNoToken)
+	assert.ErrorIs(t, logged[2].err, ErrClosed)
+}
+
+func TestWithNoTimeoutSlowServer(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		writeJSON(w, `{"uuid": "a"}`)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	limited := newTestClient(t, handler, WithTimeout(50*time.Millisecond))
+	_, err := limited.TransactionsReadOneContext(ctx, "a", nil)
+	var transportErr TransportError
+	require.True(t, errors.As(err, &transportErr), "error %v is not TransportError", err)
+	assert.True(t, transportErr.IsTimeout())
+
+	unlimited := newTestClient(t, handler, WithTimeout(50*time.Millisecond), WithNoTimeout())
+	transaction, err := unlimited.TransactionsReadOneContext(ctx, "a", nil)
+	require.NoError(t, err)
+	assert.Equal(t, "a", transaction.UUID)
+}
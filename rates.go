@@ -0,0 +1,106 @@
+package go_groshi
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrNoReferenceTransaction is returned by ImpliedRate when there is no transaction to infer the rate from.
+var ErrNoReferenceTransaction = errors.New("no transaction available to infer exchange rate from")
+
+// impliedRateLookback is how far back from now ImpliedRate looks for a reference transaction.
+const impliedRateLookback = 365 * 24 * time.Hour
+
+// ImpliedRate returns the exchange rate between currencies `from` and `to`, as used by the server.
+// The rate is inferred by reading the same transaction converted to both currencies.
+// Transactions in `from` are read in 30-day windows going back from now (the first one also includes
+// transactions timestamped in the future), up to a year back, and the transaction with the largest absolute amount
+// in the most recent window containing any non-zero transaction is picked to minimize rounding error.
+// ErrNoReferenceTransaction is returned if there is no such transaction in the last year.
+// The returned value is the number of minor units of `to` per one minor unit of `from`.
+//
+// Precision caveats: the server returns converted amounts rounded to whole minor units,
+// so the relative error of the result is up to 0.5 divided by the absolute amount of the reference transaction.
+// Rates inferred from small transactions are therefore imprecise. Note also that currencies may have
+// different numbers of decimal places (for example, USD and JPY), which is reflected in the result.
+func (c *APIClient) ImpliedRate(ctx context.Context, from string, to string) (float64, error) {
+	now := c.currentTime()
+	var reference *Transaction
+	var windowEnd *time.Time // the first window is open-ended
+	for i := 0; reference == nil; i++ {
+		if time.Duration(i)*iteratorWindow >= impliedRateLookback {
+			return 0, ErrNoReferenceTransaction
+		}
+		windowStart := now.Add(-time.Duration(i+1) * iteratorWindow)
+
+		// windows are bounded, so they are not limited by SetMaxResults:
+		transactions, err := c.readTransactions(ctx, windowStart, windowEnd, &from, TransactionsFilter{}, 0)
+		if err != nil {
+			return 0, err
+		}
+		for _, transaction := range transactions {
+			if transaction.Amount == 0 {
+				continue
+			}
+			if reference == nil || abs(transaction.Amount) > abs(reference.Amount) {
+				reference = transaction
+			}
+		}
+
+		end := c.periodEnd(windowStart)
+		windowEnd = &end
+	}
+
+	converted, err := c.TransactionsReadOneContext(ctx, reference.UUID, &to)
+	if err != nil {
+		return 0, err
+	}
+	return float64(converted.Amount) / float64(reference.Amount), nil
+}
+
+// abs returns the absolute value of x.
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+// RoundingMode determines how fractional amounts of minor units are rounded by client-side conversions.
+type RoundingMode int
+
+const (
+	RoundHalfEven RoundingMode = iota // round to the nearest integer, ties to even ("banker's rounding")
+	RoundHalfUp                       // round to the nearest integer, ties away from zero
+	RoundFloor                        // round towards negative infinity
+	RoundCeil                         // round towards positive infinity
+)
+
+// round rounds x to an integer according to the mode.
+func (m RoundingMode) round(x float64) int {
+	switch m {
+	case RoundHalfUp:
+		return int(math.Round(x))
+	case RoundFloor:
+		return int(math.Floor(x))
+	case RoundCeil:
+		return int(math.Ceil(x))
+	default:
+		return int(math.RoundToEven(x))
+	}
+}
+
+// SetRoundingMode sets the rounding mode used by client-side conversions such as ConvertAmount,
+// so that converted totals follow the accounting convention required by the user.
+// The default is RoundHalfEven (banker's rounding), which avoids bias when summing many rounded amounts.
+func (c *APIClient) SetRoundingMode(mode RoundingMode) {
+	c.roundingMode = mode
+}
+
+// ConvertAmount converts amount (in minor units) using the rate (e.g. returned by ImpliedRate)
+// and rounds the result to whole minor units according to the client's rounding mode.
+func (c *APIClient) ConvertAmount(amount int, rate float64) int {
+	return c.roundingMode.round(float64(amount) * rate)
+}
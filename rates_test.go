@@ -0,0 +1,74 @@
+package go_groshi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundingModes(t *testing.T) {
+	amounts := []int{5, 3, -5, -3} // halved: 2.5, 1.5, -2.5, -1.5
+	tests := []struct {
+		name string
+		mode RoundingMode
+		want []int
+	}{
+		{"half even", RoundHalfEven, []int{2, 2, -2, -2}},
+		{"half up", RoundHalfUp, []int{3, 2, -3, -2}},
+		{"floor", RoundFloor, []int{2, 1, -3, -2}},
+		{"ceil", RoundCeil, []int{3, 2, -2, -1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &APIClient{}
+			client.SetRoundingMode(tt.mode)
+			for i, amount := range amounts {
+				assert.Equal(t, tt.want[i], client.ConvertAmount(amount, 0.5), fmt.Sprintf("%v * 0.5", amount))
+			}
+		})
+	}
+
+	assert.Equal(t, 2, (&APIClient{}).ConvertAmount(5, 0.5), "default mode must be RoundHalfEven")
+}
+
+func TestImpliedRateReadsRecentWindows(t *testing.T) {
+	now := time.Date(2024, time.June, 1, 12, 0, 0, 0, time.UTC)
+	reads := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case r.URL.Path == "/transactions/old" && query.Get("currency") == "EUR":
+			writeJSON(w, `{"uuid": "old", "amount": -9000, "currency": "EUR"}`)
+		case r.URL.Path == "/transactions":
+			reads++
+			assert.False(t, query.Has("limit"))
+			startTime, err := time.Parse(time.RFC3339, query.Get("start_time"))
+			require.NoError(t, err)
+			if startTime.Before(now.AddDate(0, -2, 0)) {
+				writeJSON(w, `[{"uuid": "zero", "amount": 0}, {"uuid": "old", "amount": -10000}]`)
+			} else {
+				writeJSON(w, `[]`)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	client.now = func() time.Time { return now }
+	client.SetMaxResults(1)
+
+	rate, err := client.ImpliedRate(context.Background(), "USD", "EUR")
+	require.NoError(t, err)
+	assert.InDelta(t, 0.9, rate, 1e-9)
+	assert.Equal(t, 3, reads)
+
+	empty := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, `[]`)
+	})
+	_, err = empty.ImpliedRate(context.Background(), "USD", "EUR")
+	assert.ErrorIs(t, err, ErrNoReferenceTransaction)
+}
@@ -0,0 +1,103 @@
+package go_groshi
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	// autoRefreshLead is how long before the token expiry the background refresh happens.
+	autoRefreshLead = time.Minute
+
+	// autoRefreshRetryInterval and autoRefreshMaxRetryInterval are the initial and the maximum intervals
+	// between attempts of the background refresh after failures, see autoRefreshBackoff.
+	autoRefreshRetryInterval    = 10 * time.Second
+	autoRefreshMaxRetryInterval = 5 * time.Minute
+)
+
+// autoRefreshBackoff is the strategy of waiting between failed attempts of the background refresh.
+var autoRefreshBackoff Backoff = ExponentialBackoff{Initial: autoRefreshRetryInterval, Max: autoRefreshMaxRetryInterval}
+
+// isPermanentRefreshError reports whether retrying the refresh after err is pointless:
+// the token was revoked or expired, there is no token or the client was closed.
+func isPermanentRefreshError(err error) bool {
+	return errors.Is(err, ErrUnauthorized) || errors.Is(err, ErrNoToken) || errors.Is(err, ErrClosed)
+}
+
+// StartAutoRefresh starts a goroutine which refreshes the token in the background a minute before it expires
+// (see TokenExpiresAt), so that long-running services never send requests with an expired token.
+// If the expiry of the current token is unknown (e.g. it was set with SetToken), the token is refreshed immediately.
+// Failed refreshes are reported to the logger set with WithLogger, as every request, and retried after
+// 10 seconds, doubling the interval after every consecutive failure up to 5 minutes.
+// The goroutine exits when ctx is done, StopAutoRefresh is called, the server does not provide the expiry
+// of the refreshed token, or the refresh fails permanently: with ErrUnauthorized (the token was revoked or expired),
+// ErrNoToken or ErrClosed. Calling StartAutoRefresh again replaces the running goroutine.
+func (c *APIClient) StartAutoRefresh(ctx context.Context) {
+	c.StopAutoRefresh()
+
+	ctx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+
+	c.autoRefreshMutex.Lock()
+	c.autoRefreshCancel = cancel
+	c.autoRefreshDone = done
+	c.autoRefreshMutex.Unlock()
+
+	go func() {
+		defer close(done)
+		c.autoRefreshLoop(ctx)
+	}()
+}
+
+// StopAutoRefresh stops the goroutine started by StartAutoRefresh and waits for it to exit.
+// It does nothing if the goroutine is not running.
+func (c *APIClient) StopAutoRefresh() {
+	c.autoRefreshMutex.Lock()
+	cancel, done := c.autoRefreshCancel, c.autoRefreshDone
+	c.autoRefreshCancel, c.autoRefreshDone = nil, nil
+	c.autoRefreshMutex.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+}
+
+// autoRefreshLoop refreshes the token before it expires until ctx is done or the refresh fails permanently.
+func (c *APIClient) autoRefreshLoop(ctx context.Context) {
+	failures := 0 // consecutive failed attempts
+	for {
+		var wait time.Duration
+		if failures > 0 {
+			wait = autoRefreshBackoff.Next(failures)
+		} else if expiresAt := c.TokenExpiresAt(); !expiresAt.IsZero() {
+			lifetime := time.Until(expiresAt)
+			// refresh short-lived tokens in the middle of their lifetime rather than too early:
+			wait = max(lifetime-autoRefreshLead, lifetime/2)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		}
+
+		authorization, err := c.AuthRefreshContext(ctx)
+		if err != nil {
+			if isPermanentRefreshError(err) {
+				return // already reported to the logger, if any
+			}
+			failures++
+			continue
+		}
+		c.SetAuthorization(authorization)
+		failures = 0
+
+		if authorization.ExpiresAt.IsZero() {
+			return // the next refresh cannot be scheduled
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package go_groshi
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitAutoRefreshExit waits for the goroutine started by StartAutoRefresh to exit on its own.
+func waitAutoRefreshExit(t *testing.T, client *APIClient) {
+	t.Helper()
+	client.autoRefreshMutex.Lock()
+	done := client.autoRefreshDone
+	client.autoRefreshMutex.Unlock()
+	require.NotNil(t, done)
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("auto refresh goroutine did not exit")
+	}
+}
+
+func TestAutoRefreshStopsOnPermanentErrors(t *testing.T) {
+	t.Run("unauthorized", func(t *testing.T) {
+		var refreshes atomic.Int32
+		var logged atomic.Int32
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			refreshes.Add(1)
+			w.WriteHeader(http.StatusUnauthorized)
+		}, WithLogger(func(method, url string, statusCode int, duration time.Duration, err error) {
+			logged.Add(1)
+		}))
+
+		client.StartAutoRefresh(context.Background())
+		waitAutoRefreshExit(t, client)
+		assert.Equal(t, int32(1), refreshes.Load())
+		assert.Equal(t, int32(1), logged.Load())
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+			t.Error("unexpected request")
+		})
+		client.Close()
+
+		client.StartAutoRefresh(context.Background())
+		waitAutoRefreshExit(t, client)
+	})
+}
+
+func TestAutoRefreshBackoff(t *testing.T) {
+	assert.Equal(t, autoRefreshRetryInterval, autoRefreshBackoff.Next(1))
+	assert.Equal(t, 2*autoRefreshRetryInterval, autoRefreshBackoff.Next(2))
+	assert.Equal(t, autoRefreshMaxRetryInterval, autoRefreshBackoff.Next(100))
+}
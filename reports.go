@@ -0,0 +1,397 @@
+package go_groshi
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrNoTransactions is returned by aggregating methods when there are no transactions to aggregate.
+var ErrNoTransactions = errors.New("no transactions found")
+
+// ErrNoOutcomeTransactions is returned by aggregating methods when there are no outcome (expense) transactions.
+var ErrNoOutcomeTransactions = errors.New("no outcome transactions found")
+
+// AnnualMatrix returns summaries of transactions for every available currency and every month of the given year.
+// Month boundaries are computed in `loc` (UTC is used if `loc` is nil).
+// Each slice in the returned map contains twelve elements, index 0 being January.
+// Months without transactions are left as nil, and currencies without any transactions
+// during the year are not included in the map at all.
+// Summaries are fetched concurrently, so this method sends many requests at once.
+func (c *APIClient) AnnualMatrix(ctx context.Context, year int, loc *time.Location) (map[string][]*TransactionsSummary, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	currencies, err := c.CurrenciesReadContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var mutex sync.Mutex
+	matrix := make(map[string][]*TransactionsSummary)
+
+	err = forEachConcurrently(ctx, len(currencies)*12, c.concurrency, func(ctx context.Context, i int) error {
+		currency := currencies[i/12].Code
+		month := time.Month(i%12 + 1)
+
+		startTime := time.Date(year, month, 1, 0, 0, 0, 0, loc)
+		endTime := c.periodEnd(startTime.AddDate(0, 1, 0))
+
+		summary, err := c.TransactionsReadSummaryContext(ctx, currency, startTime, &endTime)
+		if err != nil {
+			return err
+		}
+		if summary.TransactionsCount == 0 {
+			return nil
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		if matrix[currency] == nil {
+			matrix[currency] = make([]*TransactionsSummary, 12)
+		}
+		matrix[currency][month-1] = summary
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return matrix, nil
+}
+
+// TransactionsReadSummaryMulti returns summaries of transactions between startTime and endTime
+// for each of the given currencies, keyed by currency, fetching them with up to SetConcurrency requests in parallel.
+// Unlike other fan-out helpers, it does not stop at the first failure: summaries of currencies
+// which were read successfully are returned along with an error joining errors of the failed currencies
+// (each prefixed with its currency), so that the returned map may be non-empty even if the error is set.
+func (c *APIClient) TransactionsReadSummaryMulti(
+	ctx context.Context, currencies []string, startTime time.Time, endTime *time.Time,
+) (map[string]*TransactionsSummary, error) {
+	results := make([]*TransactionsSummary, len(currencies))
+	errs := make([]error, len(currencies))
+	attempted := make([]bool, len(currencies))
+
+	_ = forEachConcurrently(ctx, len(currencies), c.concurrency, func(ctx context.Context, i int) error {
+		attempted[i] = true
+		results[i], errs[i] = c.TransactionsReadSummaryContext(ctx, currencies[i], startTime, endTime)
+		return nil // errors are reported per currency
+	})
+
+	summaries := make(map[string]*TransactionsSummary, len(currencies))
+	failed := make([]error, 0)
+	for i, currency := range currencies {
+		if !attempted[i] {
+			errs[i] = ctx.Err()
+		}
+		if errs[i] != nil {
+			failed = append(failed, fmt.Errorf("%v: %w", currency, errs[i]))
+			continue
+		}
+		summaries[currency] = results[i]
+	}
+	return summaries, errors.Join(failed...)
+}
+
+// isoWeekStart returns midnight of Monday which starts the first ISO 8601 week of the given year in loc.
+// The first ISO week is the one containing January 4th.
+func isoWeekStart(year int, loc *time.Location) time.Time {
+	january4 := time.Date(year, time.January, 4, 0, 0, 0, 0, loc)
+	daysSinceMonday := (int(january4.Weekday()) + 6) % 7
+	return january4.AddDate(0, 0, -daysSinceMonday)
+}
+
+// isoWeeksInYear returns the number of ISO 8601 weeks (52 or 53) in the given year.
+func isoWeeksInYear(year int) int {
+	// December 28th always belongs to the last ISO week of its year:
+	_, week := time.Date(year, time.December, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return week
+}
+
+// TransactionsByISOWeek returns summaries of transactions in the given currency for every ISO 8601 week
+// of the given ISO year, keyed by ISO week number (starting with 1).
+// Weeks start on Monday at midnight in `loc` (UTC is used if `loc` is nil), as returned by time.Time.ISOWeek.
+// Note that the first and the last ISO weeks may include days of the adjacent calendar years:
+// for example, January 1st, 2021 belongs to week 53 of ISO year 2020.
+func (c *APIClient) TransactionsByISOWeek(
+	ctx context.Context, currency string, year int, loc *time.Location,
+) (map[int]*TransactionsSummary, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	firstWeekStart := isoWeekStart(year, loc)
+	weeksCount := isoWeeksInYear(year)
+
+	var mutex sync.Mutex
+	summaries := make(map[int]*TransactionsSummary, weeksCount)
+
+	err := forEachConcurrently(ctx, weeksCount, c.concurrency, func(ctx context.Context, i int) error {
+		startTime := firstWeekStart.AddDate(0, 0, 7*i)
+		endTime := c.periodEnd(startTime.AddDate(0, 0, 7))
+
+		summary, err := c.TransactionsReadSummaryContext(ctx, currency, startTime, &endTime)
+		if err != nil {
+			return err
+		}
+
+		mutex.Lock()
+		defer mutex.Unlock()
+		summaries[i+1] = summary
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+// AmountPercentile returns the p-th percentile (0 <= p <= 1, e.g. 0.5 for the median) of amounts
+// of transactions between startTime and endTime, converted to the given currency.
+// Percentile is linearly interpolated between the closest ranks and rounded to the nearest minor unit.
+// ErrNoTransactions is returned if there are no transactions in the range.
+func (c *APIClient) AmountPercentile(
+	ctx context.Context, currency string, startTime time.Time, endTime time.Time, p float64,
+) (int, error) {
+	if p < 0 || p > 1 || math.IsNaN(p) {
+		return 0, fmt.Errorf("percentile must be in range [0, 1], got %v", p)
+	}
+
+	transactions, err := c.TransactionsReadManyContext(ctx, startTime, &endTime, &currency)
+	if err != nil {
+		return 0, err
+	}
+	if len(transactions) == 0 {
+		return 0, ErrNoTransactions
+	}
+
+	amounts := make([]int, len(transactions))
+	for i, transaction := range transactions {
+		amounts[i] = transaction.Amount
+	}
+	sort.Ints(amounts)
+
+	rank := p * float64(len(amounts)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	value := float64(amounts[lower]) + float64(amounts[upper]-amounts[lower])*(rank-float64(lower))
+	return int(math.Round(value)), nil
+}
+
+// IncomeOutcomePoint represents income and outcome during a single bucket, both as non-negative amounts.
+type IncomeOutcomePoint struct {
+	Start   time.Time
+	Income  int
+	Outcome int
+}
+
+// IncomeOutcomeSeries returns income and outcome of transactions between startTime and endTime,
+// converted to the given currency and aggregated by the given bucket.
+// Bucket boundaries are aligned in `loc` (UTC is used if `loc` is nil).
+// Every bucket overlapping the range is included, buckets without transactions have zero values.
+func (c *APIClient) IncomeOutcomeSeries(
+	ctx context.Context, currency string, startTime time.Time, endTime time.Time, bucket Bucket, loc *time.Location,
+) ([]IncomeOutcomePoint, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	transactions, err := c.TransactionsReadManyContext(ctx, startTime, &endTime, &currency)
+	if err != nil {
+		return nil, err
+	}
+
+	starts := bucket.bucketStarts(startTime, endTime, loc)
+	points := make([]IncomeOutcomePoint, len(starts))
+	for i, start := range starts {
+		points[i].Start = start
+	}
+
+	for _, transaction := range transactions {
+		i := bucketIndex(starts, transaction.Timestamp)
+		if i < 0 {
+			continue
+		}
+		if transaction.Amount > 0 {
+			points[i].Income += transaction.Amount
+		} else {
+			points[i].Outcome -= transaction.Amount
+		}
+	}
+	return points, nil
+}
+
+// BucketSummary is a summary of transactions during a single bucket of TransactionsReadSummaryGrouped.
+type BucketSummary struct {
+	Start time.Time // start of the bucket
+	TransactionsSummary
+}
+
+// TransactionsReadSummaryGrouped returns summaries of transactions between startTime and endTime,
+// converted to the given currency and grouped by the given bucket (day, week or month).
+// groshi API has no grouped summary endpoint, so the summaries are computed client-side
+// from transactions read with a single TransactionsReadMany request.
+// Bucket boundaries are aligned in `loc` (UTC is used if `loc` is nil), so that e.g. days start at local midnight.
+// Every bucket overlapping the range is included, buckets without transactions have zero values;
+// note that the first and the last buckets may extend beyond the range, but only transactions within it are counted.
+// As in IncomeOutcomeSeries, Outcome is a non-negative amount, and Total is Income minus Outcome.
+func (c *APIClient) TransactionsReadSummaryGrouped(
+	ctx context.Context, currency string, startTime time.Time, endTime time.Time, bucket Bucket, loc *time.Location,
+) ([]*BucketSummary, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	currency = c.currencyOrDefault(currency)
+
+	transactions, err := c.TransactionsReadManyContext(ctx, startTime, &endTime, &currency)
+	if err != nil {
+		return nil, err
+	}
+
+	starts := bucket.bucketStarts(startTime, endTime, loc)
+	summaries := make([]*BucketSummary, len(starts))
+	for i, start := range starts {
+		summaries[i] = &BucketSummary{Start: start, TransactionsSummary: TransactionsSummary{Currency: currency}}
+	}
+
+	for _, transaction := range transactions {
+		i := bucketIndex(starts, transaction.Timestamp)
+		if i < 0 {
+			continue
+		}
+		summary := &summaries[i].TransactionsSummary
+		if transaction.Amount > 0 {
+			summary.Income += transaction.Amount
+		} else {
+			summary.Outcome -= transaction.Amount
+		}
+		summary.Total += transaction.Amount
+		summary.TransactionsCount++
+	}
+	return summaries, nil
+}
+
+// CashflowByWeekday returns net amounts (income minus outcome) of transactions between startTime and endTime,
+// converted to the given currency and grouped by weekday of their timestamps in `loc`
+// (UTC is used if `loc` is nil). All seven weekdays are present in the returned map.
+func (c *APIClient) CashflowByWeekday(
+	ctx context.Context, currency string, startTime time.Time, endTime time.Time, loc *time.Location,
+) (map[time.Weekday]int, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	transactions, err := c.TransactionsReadManyContext(ctx, startTime, &endTime, &currency)
+	if err != nil {
+		return nil, err
+	}
+
+	cashflow := make(map[time.Weekday]int, 7)
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		cashflow[weekday] = 0
+	}
+	for _, transaction := range transactions {
+		cashflow[transaction.Timestamp.In(loc).Weekday()] += transaction.Amount
+	}
+	return cashflow, nil
+}
+
+// LargestSpendDay returns the calendar day (midnight in `loc`, UTC if `loc` is nil) with the highest total outcome
+// of transactions between startTime and endTime converted to the given currency, along with the total outcome
+// as a positive amount. If several days have the same total, the earliest one is returned.
+// ErrNoOutcomeTransactions is returned if there are no outcome transactions in the range.
+func (c *APIClient) LargestSpendDay(
+	ctx context.Context, currency string, startTime time.Time, endTime time.Time, loc *time.Location,
+) (day time.Time, total int, err error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	transactions, err := c.TransactionsReadManyContext(ctx, startTime, &endTime, &currency)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+
+	outcomeByDay := make(map[time.Time]int)
+	for _, transaction := range transactions {
+		if transaction.Amount < 0 {
+			outcomeByDay[BucketDay.start(transaction.Timestamp, loc)] -= transaction.Amount
+		}
+	}
+	if len(outcomeByDay) == 0 {
+		return time.Time{}, 0, ErrNoOutcomeTransactions
+	}
+
+	for currentDay, outcome := range outcomeByDay {
+		if outcome > total || (outcome == total && currentDay.Before(day)) {
+			day, total = currentDay, outcome
+		}
+	}
+	return day, total, nil
+}
+
+// BudgetStatusByKeyword compares spending by category during the month containing `month`
+// (boundaries are computed in month's location) to the budgets of the categories.
+// Transactions of the month are read once, converted to the given currency and categorized with SpendingByKeyword.
+// The returned map contains a status for every category of `budgets`; categories without
+// keywords or without spending are reported with zero Spent.
+func (c *APIClient) BudgetStatusByKeyword(
+	ctx context.Context, currency string, month time.Time, budgets map[string]int, keywords map[string][]string,
+) (map[string]*BudgetStatus, error) {
+	startTime := BucketMonth.start(month, month.Location())
+	endTime := c.periodEnd(BucketMonth.next(startTime))
+
+	transactions, err := c.TransactionsReadManyContext(ctx, startTime, &endTime, &currency)
+	if err != nil {
+		return nil, err
+	}
+
+	spending := SpendingByKeyword(transactions, keywords)
+	statuses := make(map[string]*BudgetStatus, len(budgets))
+	for category, budget := range budgets {
+		statuses[category] = newBudgetStatus(category, budget, spending[category])
+	}
+	return statuses, nil
+}
+
+// MAPoint represents the moving average of daily outcome at the given day.
+type MAPoint struct {
+	Date    time.Time // midnight of the day
+	Average float64   // average daily outcome (as a positive amount) over the window ending at Date
+}
+
+// DailySpendMovingAverage returns the `window`-day simple moving average of daily outcome
+// of transactions between startTime and endTime, converted to the given currency.
+// Days are calendar days in `loc` (UTC is used if `loc` is nil), days without outcome count as zero.
+// For the leading days, where fewer than `window` days are available, the average is taken over the available days.
+func (c *APIClient) DailySpendMovingAverage(
+	ctx context.Context, currency string, startTime time.Time, endTime time.Time, window int, loc *time.Location,
+) ([]MAPoint, error) {
+	if window < 1 {
+		return nil, fmt.Errorf("moving average window must be positive, got %v", window)
+	}
+
+	series, err := c.IncomeOutcomeSeries(ctx, currency, startTime, endTime, BucketDay, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]MAPoint, len(series))
+	sum := 0
+	for i, point := range series {
+		sum += point.Outcome
+		if i >= window {
+			sum -= series[i-window].Outcome
+		}
+		days := window
+		if i+1 < window {
+			days = i + 1
+		}
+		points[i] = MAPoint{Date: point.Start, Average: float64(sum) / float64(days)}
+	}
+	return points, nil
+}
@@ -0,0 +1,51 @@
+package go_groshi
+
+import "time"
+
+// Request bodies of groshi API methods. Optional fields are pointers with omitempty,
+// so that only the fields provided by the caller are sent.
+
+// credentialsRequest is the body of AuthLogin and UserCreate requests.
+type credentialsRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// userUpdateRequest is the body of UserUpdate requests.
+type userUpdateRequest struct {
+	CurrentPassword *string `json:"current_password,omitempty"`
+	NewUsername     *string `json:"new_username,omitempty"`
+	NewPassword     *string `json:"new_password,omitempty"`
+}
+
+// transactionCreateRequest is the body of TransactionsCreate (and TransactionsValidate) requests.
+type transactionCreateRequest struct {
+	Amount      int     `json:"amount"`
+	Currency    string  `json:"currency"`
+	Description *string `json:"description,omitempty"`
+	Timestamp   *string `json:"timestamp,omitempty"` // formatted with the client's time format
+}
+
+// transactionUpdateRequest is the body of TransactionsUpdate requests.
+type transactionUpdateRequest struct {
+	NewAmount      *int    `json:"new_amount,omitempty"`
+	NewCurrency    *string `json:"new_currency,omitempty"`
+	NewDescription *string `json:"new_description,omitempty"`
+	NewTimestamp   *string `json:"new_timestamp,omitempty"` // formatted with the client's time format
+}
+
+// newTransactionCreateRequest returns body of the request creating a transaction with the given parameters.
+func (c *APIClient) newTransactionCreateRequest(
+	amount int, currency string, description *string, timestamp *time.Time,
+) transactionCreateRequest {
+	request := transactionCreateRequest{
+		Amount:      amount,
+		Currency:    c.currencyOrDefault(currency),
+		Description: description,
+	}
+	if timestamp != nil {
+		formatted := c.formatTime((*timestamp).UTC())
+		request.Timestamp = &formatted
+	}
+	return request
+}
@@ -0,0 +1,73 @@
+package go_groshi
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestBodiesJSON(t *testing.T) {
+	client := &APIClient{timeFormat: timeFormat}
+	description := "coffee"
+	timestamp := time.Date(2023, time.March, 5, 14, 30, 0, 0, time.FixedZone("UTC+1", 60*60))
+	newAmount, newCurrency, newPassword := -250, "EUR", "new-password"
+
+	tests := []struct {
+		name string
+		body any
+		want string
+	}{
+		{"credentials", credentialsRequest{Username: "user", Password: "password"}, `{"username": "user", "password": "password"}`},
+		{"user update, nothing", userUpdateRequest{}, `{}`},
+		{"user update, password", userUpdateRequest{NewPassword: &newPassword}, `{"new_password": "new-password"}`},
+		{
+			"transaction create, minimal",
+			client.newTransactionCreateRequest(-100, "USD", nil, nil),
+			`{"amount": -100, "currency": "USD"}`,
+		},
+		{
+			"transaction create, full",
+			client.newTransactionCreateRequest(-100, "USD", &description, &timestamp),
+			`{"amount": -100, "currency": "USD", "description": "coffee", "timestamp": "2023-03-05T13:30:00Z"}`,
+		},
+		{"transaction update, nothing", transactionUpdateRequest{}, `{}`},
+		{
+			"transaction update, amount and currency",
+			transactionUpdateRequest{NewAmount: &newAmount, NewCurrency: &newCurrency},
+			`{"new_amount": -250, "new_currency": "EUR"}`,
+		},
+		{
+			"transaction update, description",
+			transactionUpdateRequest{NewDescription: &description},
+			`{"new_description": "coffee"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.body)
+			require.NoError(t, err)
+			assert.JSONEq(t, tt.want, string(data))
+		})
+	}
+}
+
+func TestTransactionsUpdateBody(t *testing.T) {
+	var body string
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		data, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		body = string(data)
+		writeJSON(w, `{"uuid": "a"}`)
+	})
+
+	newAmount, newCurrency := -250, "EUR"
+	newTimestamp := time.Date(2023, time.March, 5, 14, 30, 0, 0, time.UTC)
+	_, err := client.TransactionsUpdate("a", &newAmount, &newCurrency, nil, &newTimestamp)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"new_amount": -250, "new_currency": "EUR", "new_timestamp": "2023-03-05T14:30:00Z"}`, body)
+}
@@ -0,0 +1,268 @@
+package go_groshi
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryConfig configures retrying of failed requests.
+// Requests are retried on network errors and on 429, 502, 503 and 504 responses,
+// but only if their HTTP method is idempotent (GET, PUT and DELETE).
+// Between attempts, the client waits according to the Backoff, or as long as the server requests
+// with the Retry-After header. When retries are disabled, the requested duration is available as APIError.RetryAfter.
+type RetryConfig struct {
+	// MaxRetries is the maximum number of times a failed request is retried.
+	// Zero (the default) disables retries.
+	MaxRetries int
+
+	// MaxRetriesByStatus overrides MaxRetries for responses with particular HTTP status codes,
+	// e.g. to retry 503 Service Unavailable (server warming up) more generously than 502 Bad Gateway.
+	// The limit is determined by the status of the latest failed attempt and counts all previous retries.
+	MaxRetriesByStatus map[int]int
+}
+
+// enabled reports whether the config allows retrying any failed requests.
+func (config RetryConfig) enabled() bool {
+	if config.MaxRetries > 0 {
+		return true
+	}
+	for _, maxRetries := range config.MaxRetriesByStatus {
+		if maxRetries > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// maxRetries returns the maximum number of retries after the failed attempt.
+func (config RetryConfig) maxRetries(response *http.Response) int {
+	if response != nil {
+		if maxRetries, ok := config.MaxRetriesByStatus[response.StatusCode]; ok {
+			return maxRetries
+		}
+	}
+	return config.MaxRetries
+}
+
+// Backoff determines how long to wait before retrying a failed request.
+type Backoff interface {
+	// Next returns the delay before the given retry attempt, attempt being 1 for the first retry.
+	Next(attempt int) time.Duration
+}
+
+// ConstantBackoff waits the same Delay before every retry.
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+func (b ConstantBackoff) Next(attempt int) time.Duration {
+	return b.Delay
+}
+
+// ExponentialBackoff doubles the delay with every retry, starting from Initial and never exceeding Max.
+// Zero Max means that the delay is not limited.
+type ExponentialBackoff struct {
+	Initial time.Duration
+	Max     time.Duration
+}
+
+func (b ExponentialBackoff) Next(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	delay := float64(b.Initial) * math.Pow(2, float64(attempt-1))
+	if b.Max > 0 && delay > float64(b.Max) {
+		return b.Max
+	}
+	if delay >= math.MaxInt64 {
+		return time.Duration(math.MaxInt64)
+	}
+	return time.Duration(delay)
+}
+
+// ExponentialJitterBackoff waits a random delay between zero and the delay of ExponentialBackoff
+// with the same parameters ("full jitter"), which spreads retries of many clients over time.
+type ExponentialJitterBackoff struct {
+	Initial time.Duration
+	Max     time.Duration
+}
+
+func (b ExponentialJitterBackoff) Next(attempt int) time.Duration {
+	delay := ExponentialBackoff{Initial: b.Initial, Max: b.Max}.Next(attempt)
+	if delay <= 0 {
+		return 0
+	}
+	if delay == math.MaxInt64 {
+		return time.Duration(rand.Int63n(int64(delay))) // delay+1 would overflow
+	}
+	return time.Duration(rand.Int63n(int64(delay) + 1))
+}
+
+// defaultBackoff is used when retries are enabled but no Backoff was set.
+var defaultBackoff Backoff = ExponentialJitterBackoff{Initial: 100 * time.Millisecond, Max: 5 * time.Second}
+
+// SetRetryConfig sets configuration of retrying failed requests. Retries are disabled by default.
+func (c *APIClient) SetRetryConfig(config RetryConfig) {
+	c.retryConfig = config
+}
+
+// SetBackoff sets the strategy of waiting between retries of failed requests.
+// By default, ExponentialJitterBackoff starting at 100 milliseconds and limited to 5 seconds is used.
+func (c *APIClient) SetBackoff(backoff Backoff) {
+	c.backoff = backoff
+}
+
+// RetryPredicate decides whether a failed attempt should be retried.
+// Exactly one of response and err is non-nil.
+type RetryPredicate func(response *http.Response, err error) bool
+
+// SetRetryPredicate overrides the built-in decision of which failures are retried.
+// The predicate is consulted only when retries are enabled with SetRetryConfig,
+// and it is called for requests of every HTTP method, including non-idempotent POST requests
+// such as TransactionsCreate: retrying those may perform the operation twice,
+// so the predicate must reject them unless the caller accepts the risk
+// (TransactionsCreate sends an idempotency key when retries are enabled, which averts the risk
+// only if the server honors it).
+// Requests whose context is done are never retried. Pass nil to restore the built-in behaviour.
+func (c *APIClient) SetRetryPredicate(predicate RetryPredicate) {
+	c.retryPredicate = predicate
+}
+
+// isIdempotentMethod reports whether requests with the given HTTP method are safe to repeat.
+func isIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRetryable reports whether the failed attempt described by response and err is worth retrying.
+func isRetryable(response *http.Response, err error) bool {
+	if err != nil {
+		return true
+	}
+	switch response.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// shouldRetry reports whether the failed attempt should be retried, consulting the retry predicate if it is set.
+func (c *APIClient) shouldRetry(request *http.Request, response *http.Response, err error) bool {
+	if c.retryPredicate != nil {
+		return c.retryPredicate(response, err)
+	}
+	return (isIdempotentMethod(request.Method) || safeToRetry(request.Context())) && isRetryable(response, err)
+}
+
+type noRetriesContextKey struct{}
+
+// withoutRetries returns a copy of ctx for which failed requests are never retried.
+func withoutRetries(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetriesContextKey{}, true)
+}
+
+// retriesDisabled reports whether retries are disabled for requests with the context.
+func retriesDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(noRetriesContextKey{}).(bool)
+	return disabled
+}
+
+type safeToRetryContextKey struct{}
+
+// withSafeToRetry returns a copy of ctx marking requests as safe to retry regardless of their HTTP method.
+func withSafeToRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, safeToRetryContextKey{}, true)
+}
+
+// safeToRetry reports whether requests with the context are marked as safe to retry by withSafeToRetry.
+func safeToRetry(ctx context.Context) bool {
+	safe, _ := ctx.Value(safeToRetryContextKey{}).(bool)
+	return safe
+}
+
+// parseRetryAfter parses value of the Retry-After header, either delta-seconds or HTTP-date,
+// and returns the duration to wait from now. Dates in the past yield zero duration.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if wait := date.Sub(now); wait > 0 {
+			return wait, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// doWithRetries sends requests produced by newRequest until one of them succeeds or retries are exhausted.
+// If a failed response has the Retry-After header, the requested duration is waited instead of the backoff.
+// If the wait would not end before the deadline of ctx, the failed response is returned without waiting.
+// Errors of the last attempt, as well as the error of ctx done while waiting, are wrapped in TransportError.
+func (c *APIClient) doWithRetries(
+	ctx context.Context, httpClient *http.Client, newRequest func() (*http.Request, error),
+) (*http.Response, error) {
+	backoff := c.backoff
+	if backoff == nil {
+		backoff = defaultBackoff
+	}
+
+	for attempt := 0; ; attempt++ {
+		request, err := newRequest()
+		if err != nil {
+			return nil, err
+		}
+
+		response, err := httpClient.Do(request)
+		if retriesDisabled(ctx) || attempt >= c.retryConfig.maxRetries(response) || ctx.Err() != nil || !c.shouldRetry(request, response, err) {
+			if err != nil {
+				return nil, TransportError{Err: err}
+			}
+			return response, nil
+		}
+
+		delay := backoff.Next(attempt + 1)
+		if response != nil {
+			if retryAfter, ok := parseRetryAfter(response.Header.Get("Retry-After"), time.Now()); ok {
+				delay = retryAfter
+			}
+		}
+		if deadline, ok := ctx.Deadline(); ok && response != nil && time.Until(deadline) < delay {
+			// the retry would not happen before the deadline, so the failed response is returned instead,
+			// which lets the caller see the APIError along with its RetryAfter:
+			return response, nil
+		}
+
+		if response != nil {
+			// drain the body so that the connection can be reused:
+			_, _ = io.Copy(io.Discard, response.Body)
+			_ = response.Body.Close()
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, TransportError{Err: ctx.Err()}
+		}
+	}
+}
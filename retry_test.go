@@ -0,0 +1,94 @@
+package go_groshi
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryAfterBeyondDeadline(t *testing.T) {
+	attempts := 0
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+		writeJSON(w, `{"error_message": "too many requests"}`)
+	}, WithTimeout(time.Second), WithRetryConfig(RetryConfig{MaxRetries: 3}))
+
+	startTime := time.Now()
+	_, err := client.UserRead()
+	assert.Less(t, time.Since(startTime), time.Second)
+
+	var apiErr APIError
+	require.True(t, errors.As(err, &apiErr), "error %v is not APIError", err)
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.HTTPStatusCode)
+	assert.Equal(t, 30*time.Second, apiErr.RetryAfter)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestBackoffTimeoutIsTransportError(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close() // connections are refused, so that the request is retried after the backoff
+
+	client, err := NewClient(
+		server.URL,
+		WithToken("token"),
+		WithTimeout(100*time.Millisecond),
+		WithRetryConfig(RetryConfig{MaxRetries: 3}),
+		WithBackoff(ConstantBackoff{Delay: time.Second}),
+	)
+	require.NoError(t, err)
+
+	_, err = client.UserRead()
+	var transportErr TransportError
+	require.True(t, errors.As(err, &transportErr), "error %v is not TransportError", err)
+	assert.True(t, transportErr.IsTimeout())
+}
+
+func TestMaxRetriesByStatus(t *testing.T) {
+	config := RetryConfig{
+		MaxRetries:         1,
+		MaxRetriesByStatus: map[int]int{http.StatusServiceUnavailable: 4, http.StatusBadGateway: 0},
+	}
+	tests := []struct {
+		status       int
+		wantAttempts int
+	}{
+		{http.StatusServiceUnavailable, 5},
+		{http.StatusBadGateway, 1},
+		{http.StatusGatewayTimeout, 2}, // MaxRetries
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			attempts := 0
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				attempts++
+				w.WriteHeader(tt.status)
+			}, WithRetryConfig(config), WithBackoff(ConstantBackoff{Delay: time.Millisecond}))
+
+			_, err := client.UserRead()
+			var apiErr APIError
+			require.True(t, errors.As(err, &apiErr), "error %v is not APIError", err)
+			assert.Equal(t, tt.status, apiErr.HTTPStatusCode)
+			assert.Equal(t, tt.wantAttempts, attempts)
+		})
+	}
+
+	assert.False(t, RetryConfig{}.enabled())
+	assert.True(t, RetryConfig{MaxRetriesByStatus: map[int]int{http.StatusServiceUnavailable: 1}}.enabled())
+}
+
+func TestExponentialJitterBackoffUnlimited(t *testing.T) {
+	backoff := ExponentialJitterBackoff{Initial: time.Second}
+	for _, attempt := range []int{1, 32, 64, 1000} {
+		assert.NotPanics(t, func() {
+			delay := backoff.Next(attempt)
+			assert.GreaterOrEqual(t, delay, time.Duration(0))
+		})
+	}
+}
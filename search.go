@@ -0,0 +1,58 @@
+package go_groshi
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+)
+
+// ErrEmptySearchQuery is returned by Search when the query is empty.
+var ErrEmptySearchQuery = errors.New("search query is empty")
+
+// matchesSearch reports whether the transaction matches the search query, see Search.
+func (c *APIClient) matchesSearch(transaction *Transaction, query string) bool {
+	if strings.Contains(strings.ToLower(transaction.Description), strings.ToLower(query)) {
+		return true
+	}
+
+	amount, err := parseAmount(query, c.decimalPlaces(transaction.Currency))
+	if err != nil {
+		return false
+	}
+	if strings.HasPrefix(query, "-") || strings.HasPrefix(query, "+") {
+		return transaction.Amount == amount
+	}
+	return abs(transaction.Amount) == amount
+}
+
+// Search returns transactions between startTime and endTime matching the query, newest first.
+// A transaction matches if either:
+//   - its description contains the query, ignoring case, or
+//   - the query is a decimal number in major units (see ParseAmount) equal to the transaction's amount
+//     in its currency. Without a sign, the query matches both income and outcome ("12.5" matches 12.50 and -12.50
+//     in USD), with a sign it matches only amounts of that sign ("-12.5" matches only -12.50).
+//
+// An ambiguous query, such as "100", matches both descriptions containing it and amounts equal to it.
+// Surrounding whitespace of the query is ignored; ErrEmptySearchQuery is returned if nothing remains.
+// Transactions are read with a single request and matched client-side.
+func (c *APIClient) Search(ctx context.Context, query string, startTime time.Time, endTime time.Time) ([]*Transaction, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrEmptySearchQuery
+	}
+
+	transactions, err := c.TransactionsReadManyContext(ctx, startTime, &endTime, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	matching := make([]*Transaction, 0)
+	for _, transaction := range transactions {
+		if c.matchesSearch(transaction, query) {
+			matching = append(matching, transaction)
+		}
+	}
+	SortTimestampDesc.sort(matching)
+	return matching, nil
+}
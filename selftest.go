@@ -0,0 +1,49 @@
+package go_groshi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+)
+
+// unicodeSelfTestDescription contains emoji, CJK, right-to-left and combining characters.
+const unicodeSelfTestDescription = "go-groshi self-test: Zażółć gęślą jaźń 🚀👩‍👩‍👧 日本語 中文 한국어 עברית العربية é"
+
+// ErrUnicodeMismatch is returned by SelfTestUnicode when the server does not return the description unchanged.
+var ErrUnicodeMismatch = errors.New("description was not round-tripped unchanged")
+
+// SelfTestUnicode checks that the server stores non-ASCII text without mangling it.
+// It creates a temporary transaction with a description containing various Unicode characters,
+// reads it back, compares the descriptions byte by byte and finally deletes the transaction.
+// The transaction is deleted even if the comparison fails.
+func (c *APIClient) SelfTestUnicode(ctx context.Context) (err error) {
+	currencies, err := c.CurrenciesReadContext(ctx)
+	if err != nil {
+		return err
+	}
+	if len(currencies) == 0 {
+		return errors.New("server provides no currencies to create test transaction with")
+	}
+
+	description := unicodeSelfTestDescription
+	created, err := c.TransactionsCreateContext(ctx, 1, currencies[0].Code, &description, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if _, deleteErr := c.TransactionsDeleteContext(ctx, created.UUID); deleteErr != nil && err == nil {
+			err = fmt.Errorf("failed to delete test transaction %v: %w", created.UUID, deleteErr)
+		}
+	}()
+
+	read, err := c.TransactionsReadOneContext(ctx, created.UUID, nil)
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal([]byte(read.Description), []byte(description)) {
+		return fmt.Errorf("%w: sent %q, received %q", ErrUnicodeMismatch, description, read.Description)
+	}
+	return nil
+}
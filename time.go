@@ -0,0 +1,157 @@
+package go_groshi
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// timeLayouts are layouts of timestamps accepted when decoding responses, in order of preference.
+// Besides RFC-3339, they cover variants produced by some servers and proxies:
+// a space instead of 'T', numeric offsets without a colon and timestamps without a zone (treated as UTC).
+// Fractional seconds are accepted after the seconds field with every layout.
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05Z0700",
+	"2006-01-02T15:04:05Z07",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05Z0700",
+	"2006-01-02 15:04:05Z07",
+	"2006-01-02 15:04:05",
+}
+
+// SetTimeFormat sets the layout (see time.Layout) used to format timestamps sent to groshi API,
+// both in query params and in request bodies, e.g. time.RFC3339Nano for servers expecting nanosecond precision.
+// The default is time.RFC3339. Responses are decoded tolerantly regardless of this setting.
+func (c *APIClient) SetTimeFormat(layout string) {
+	c.timeFormat = layout
+}
+
+// formatTime formats t according to the client's time format.
+func (c *APIClient) formatTime(t time.Time) string {
+	return t.Format(c.timeFormat)
+}
+
+// timePrecision returns the smallest difference between timestamps distinguishable after formatting them
+// according to the client's time format, e.g. time.Second for time.RFC3339 and time.Nanosecond for time.RFC3339Nano.
+// It is determined by formatting and parsing back the last nanosecond of a year.
+func (c *APIClient) timePrecision() time.Duration {
+	reference := time.Date(2000, time.December, 31, 23, 59, 59, 999999999, time.UTC)
+	parsed, err := time.Parse(c.timeFormat, reference.Format(c.timeFormat))
+	if err != nil || parsed.After(reference) {
+		return time.Second
+	}
+	return reference.Sub(parsed) + time.Nanosecond
+}
+
+// periodEnd returns the last moment before `next` representable in the client's time format (see timePrecision).
+// It is used to express [start, next) periods via the inclusive end_time query param.
+func (c *APIClient) periodEnd(next time.Time) time.Time {
+	return next.Add(-c.timePrecision())
+}
+
+// currentTime returns the current time, which can be replaced in tests.
+func (c *APIClient) currentTime() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// SetLocation sets the location which timestamps of decoded responses (Transaction.Timestamp, CreatedAt
+// and UpdatedAt, Authorization.ExpiresAt) are converted to, e.g. time.Local for display or date filtering
+// in local time. Conversion does not change the instants. The default is UTC; nil means UTC too.
+func (c *APIClient) SetLocation(loc *time.Location) {
+	c.location = loc
+}
+
+// convertTimes converts timestamps of the decoded response v into the location set by SetLocation.
+func (c *APIClient) convertTimes(v any) {
+	if c.location == nil || c.location == time.UTC {
+		return // timestamps are decoded in UTC
+	}
+
+	convertTransaction := func(transaction *Transaction) {
+		transaction.Timestamp = transaction.Timestamp.In(c.location)
+		transaction.CreatedAt = transaction.CreatedAt.In(c.location)
+		transaction.UpdatedAt = transaction.UpdatedAt.In(c.location)
+	}
+	switch v := v.(type) {
+	case *Transaction:
+		convertTransaction(v)
+	case *[]*Transaction:
+		for _, transaction := range *v {
+			if transaction != nil {
+				convertTransaction(transaction)
+			}
+		}
+	case *Authorization:
+		v.ExpiresAt = v.ExpiresAt.In(c.location)
+	}
+}
+
+// parseTime parses timestamp in any of timeLayouts and returns it in UTC.
+func parseTime(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse %q as timestamp", value)
+}
+
+// jsonTime is time.Time decoded from JSON tolerantly, see parseTime.
+type jsonTime time.Time
+
+func (t *jsonTime) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		*t = jsonTime{}
+		return nil
+	}
+
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	parsed, err := parseTime(value)
+	if err != nil {
+		return err
+	}
+	*t = jsonTime(parsed)
+	return nil
+}
+
+func (a *Authorization) UnmarshalJSON(data []byte) error {
+	type authorization Authorization // prevents recursion
+	aux := struct {
+		*authorization
+		ExpiresAt jsonTime `json:"expires_at"`
+	}{authorization: (*authorization)(a)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	a.ExpiresAt = time.Time(aux.ExpiresAt)
+	return nil
+}
+
+func (t *Transaction) UnmarshalJSON(data []byte) error {
+	type transaction Transaction // prevents recursion
+	aux := struct {
+		*transaction
+		Timestamp jsonTime `json:"timestamp"`
+		CreatedAt jsonTime `json:"created_at"`
+		UpdatedAt jsonTime `json:"updated_at"`
+	}{transaction: (*transaction)(t)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	t.Timestamp = time.Time(aux.Timestamp)
+	t.CreatedAt = time.Time(aux.CreatedAt)
+	t.UpdatedAt = time.Time(aux.UpdatedAt)
+	return nil
+}
@@ -0,0 +1,155 @@
+package go_groshi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+	_ "time/tzdata" // America/New_York must be available regardless of the system's database
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeFormatQueryParams(t *testing.T) {
+	startTime := time.Date(2023, time.January, 31, 23, 59, 59, 500000000, time.UTC)
+	tests := []struct {
+		name      string
+		opts      []Option
+		wantStart string
+	}{
+		{"default", nil, "2023-01-31T23:59:59Z"},
+		{"RFC3339Nano", []Option{WithTimeFormat(time.RFC3339Nano)}, "2023-01-31T23:59:59.5Z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query url.Values
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				query = r.URL.Query()
+				writeJSON(w, `[]`)
+			}, tt.opts...)
+
+			_, err := client.TransactionsReadMany(startTime, nil, nil)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStart, query.Get("start_time"))
+		})
+	}
+}
+
+func TestPeriodEndPrecision(t *testing.T) {
+	next := time.Date(2023, time.February, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		layout string
+		want   time.Time
+	}{
+		{time.RFC3339, next.Add(-time.Second)},
+		{time.RFC3339Nano, next.Add(-time.Nanosecond)},
+		{"2006-01-02T15:04:05.000Z07:00", next.Add(-time.Millisecond)},
+		{"2006-01-02T15:04Z07:00", next.Add(-time.Minute)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.layout, func(t *testing.T) {
+			client := &APIClient{timeFormat: tt.layout}
+			assert.Equal(t, tt.want, client.periodEnd(next))
+		})
+	}
+}
+
+func TestPeriodEndIncludesSubsecondTransactions(t *testing.T) {
+	var query url.Values
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		writeJSON(w, `[]`)
+	}, WithTimeFormat(time.RFC3339Nano))
+
+	month := time.Date(2023, time.January, 15, 0, 0, 0, 0, time.UTC)
+	_, err := client.BudgetStatusByKeyword(context.Background(), "USD", month, nil, nil)
+	require.NoError(t, err)
+
+	endTime, err := time.Parse(time.RFC3339Nano, query.Get("end_time"))
+	require.NoError(t, err)
+	lastTransaction := time.Date(2023, time.January, 31, 23, 59, 59, 500000000, time.UTC)
+	assert.False(t, endTime.Before(lastTransaction), "end_time %v excludes %v", endTime, lastTransaction)
+}
+
+func TestTolerantTimestamps(t *testing.T) {
+	want := time.Date(2023, time.March, 5, 14, 30, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		timestamp string
+	}{
+		{"RFC3339", "2023-03-05T14:30:00Z"},
+		{"space instead of T", "2023-03-05 14:30:00Z"},
+		{"no zone", "2023-03-05T14:30:00"},
+		{"space and no zone", "2023-03-05 14:30:00"},
+		{"offset without colon", "2023-03-05T15:30:00+0100"},
+		{"hour offset", "2023-03-05T15:30:00+01"},
+		{"fractional seconds", "2023-03-05 15:30:00.000+0100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var transaction Transaction
+			require.NoError(t, json.Unmarshal([]byte(`{"timestamp": "`+tt.timestamp+`"}`), &transaction))
+			assert.Equal(t, want, transaction.Timestamp)
+		})
+	}
+
+	var transaction Transaction
+	assert.Error(t, json.Unmarshal([]byte(`{"timestamp": "05.03.2023"}`), &transaction))
+}
+
+func TestTransactionsAddTodayTimezoneBoundaries(t *testing.T) {
+	tokyo := time.FixedZone("UTC+9", 9*60*60)
+	losAngeles := time.FixedZone("UTC-8", -8*60*60)
+	tests := []struct {
+		name          string
+		now           time.Time
+		loc           *time.Location
+		wantTimestamp string
+		wantDay       int // day of March in loc
+	}{
+		{"before UTC midnight, next day in Tokyo", time.Date(2023, time.March, 5, 23, 30, 0, 0, time.UTC), tokyo, "2023-03-05T23:30:00Z", 6},
+		{"after UTC midnight, previous day in Los Angeles", time.Date(2023, time.March, 6, 0, 30, 0, 0, time.UTC), losAngeles, "2023-03-06T00:30:00Z", 5},
+		{"local midnight in Tokyo", time.Date(2023, time.March, 5, 15, 0, 0, 0, time.UTC), tokyo, "2023-03-05T15:00:00Z", 6},
+		{"a moment before local midnight in Tokyo", time.Date(2023, time.March, 5, 14, 59, 59, 0, time.UTC), tokyo, "2023-03-05T14:59:59Z", 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var body transactionCreateRequest
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				writeJSON(w, `{"uuid": "created"}`)
+			})
+			client.now = func() time.Time { return tt.now }
+
+			_, err := client.TransactionsAddToday(context.Background(), -100, "USD", "coffee", tt.loc)
+			require.NoError(t, err)
+			require.NotNil(t, body.Timestamp)
+			assert.Equal(t, tt.wantTimestamp, *body.Timestamp)
+
+			timestamp, err := time.Parse(time.RFC3339, *body.Timestamp)
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantDay, timestamp.In(tt.loc).Day())
+		})
+	}
+}
+
+func TestLocationConversion(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, `[{"uuid": "a", "timestamp": "2023-03-05T03:30:00Z", "created_at": "2023-07-01T12:00:00Z"}]`)
+	}, WithLocation(newYork))
+
+	transactions, err := client.TransactionsReadMany(time.Date(2023, time.March, 1, 0, 0, 0, 0, time.UTC), nil, nil)
+	require.NoError(t, err)
+	require.Len(t, transactions, 1)
+
+	timestamp := transactions[0].Timestamp
+	assert.Equal(t, newYork, timestamp.Location())
+	assert.Equal(t, "2023-03-04T22:30:00-05:00", timestamp.Format(time.RFC3339)) // the previous day in New York
+	assert.True(t, timestamp.Equal(time.Date(2023, time.March, 5, 3, 30, 0, 0, time.UTC)), "the instant must not change")
+	assert.Equal(t, "2023-07-01T08:00:00-04:00", transactions[0].CreatedAt.Format(time.RFC3339)) // daylight saving time
+}
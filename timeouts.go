@@ -0,0 +1,49 @@
+package go_groshi
+
+import (
+	"strings"
+	"time"
+)
+
+// Categories of operations, see SetOperationTimeout.
+const (
+	OperationAuth         = "auth"         // logging in and refreshing the token
+	OperationUser         = "user"         // creating, reading, updating and deleting the user
+	OperationTransactions = "transactions" // creating, reading, updating and deleting transactions
+	OperationSummary      = "summary"      // summaries of transactions
+	OperationCurrencies   = "currencies"   // reading available currencies, including Ping
+	OperationLimits       = "limits"       // reading limits of the server
+)
+
+// operationCategory returns the category of operations the request to the given path belongs to,
+// or an empty string if the path belongs to none of them.
+func operationCategory(path string) string {
+	if path == summaryPath {
+		return OperationSummary
+	}
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	switch segment {
+	case OperationAuth, OperationUser, OperationTransactions, OperationCurrencies, OperationLimits:
+		return segment
+	default:
+		return ""
+	}
+}
+
+// SetOperationTimeout sets the time limit for requests of the given category of operations
+// (one of Operation* constants, e.g. OperationSummary), which overrides the timeout set by SetTimeout for them.
+// As with SetTimeout, zero means no timeout. Categories without an override use the timeout set by SetTimeout.
+func (c *APIClient) SetOperationTimeout(category string, timeout time.Duration) {
+	if c.operationTimeouts == nil {
+		c.operationTimeouts = make(map[string]time.Duration)
+	}
+	c.operationTimeouts[category] = timeout
+}
+
+// timeoutFor returns the time limit of the request to the given path, see SetOperationTimeout.
+func (c *APIClient) timeoutFor(path string) time.Duration {
+	if timeout, ok := c.operationTimeouts[operationCategory(path)]; ok {
+		return timeout
+	}
+	return c.timeout
+}
@@ -0,0 +1,69 @@
+package go_groshi
+
+import "fmt"
+
+// dedupTransactions removes transactions with repeated UUIDs, keeping the first occurrence of each one.
+// Order of the remaining transactions is preserved. The input slice is not modified.
+func dedupTransactions(transactions []*Transaction) []*Transaction {
+	seen := make(map[string]struct{}, len(transactions))
+	result := make([]*Transaction, 0, len(transactions))
+	for _, transaction := range transactions {
+		if _, ok := seen[transaction.UUID]; ok {
+			continue
+		}
+		seen[transaction.UUID] = struct{}{}
+		result = append(result, transaction)
+	}
+	return result
+}
+
+// DedupTransactions returns transactions without duplicates (by UUID), keeping the first occurrence of each one
+// and preserving order. It is useful to merge results of reads with overlapping time ranges.
+func DedupTransactions(transactions []*Transaction) []*Transaction {
+	return dedupTransactions(transactions)
+}
+
+// Direction is the direction of money flow of a transaction. By convention of groshi API,
+// positive amounts are income, negative amounts are outcome (expenses).
+type Direction int
+
+const (
+	DirectionNone    Direction = iota // zero amount
+	DirectionIncome                   // positive amount
+	DirectionOutcome                  // negative amount
+)
+
+func (d Direction) String() string {
+	switch d {
+	case DirectionNone:
+		return "none"
+	case DirectionIncome:
+		return "income"
+	case DirectionOutcome:
+		return "outcome"
+	default:
+		return fmt.Sprintf("Direction(%d)", int(d))
+	}
+}
+
+// Direction returns direction of the transaction determined by the sign of its amount.
+func (t *Transaction) Direction() Direction {
+	switch {
+	case t.Amount > 0:
+		return DirectionIncome
+	case t.Amount < 0:
+		return DirectionOutcome
+	default:
+		return DirectionNone
+	}
+}
+
+// IsIncome reports whether the transaction is income, i.e. its amount is positive.
+func (t *Transaction) IsIncome() bool {
+	return t.Direction() == DirectionIncome
+}
+
+// IsOutcome reports whether the transaction is outcome (an expense), i.e. its amount is negative.
+func (t *Transaction) IsOutcome() bool {
+	return t.Direction() == DirectionOutcome
+}
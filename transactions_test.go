@@ -0,0 +1,69 @@
+package go_groshi
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDedupTransactionsOverlappingChunks(t *testing.T) {
+	newTransaction := func(uuid string) *Transaction {
+		return &Transaction{UUID: uuid}
+	}
+	a, b, c, d, e := newTransaction("a"), newTransaction("b"), newTransaction("c"), newTransaction("d"), newTransaction("e")
+	bDuplicate, cDuplicate := newTransaction("b"), newTransaction("c")
+
+	chunks := [][]*Transaction{{a, b, c}, {cDuplicate, d}, {bDuplicate, e}}
+	merged := make([]*Transaction, 0)
+	for _, chunk := range chunks {
+		merged = append(merged, chunk...)
+	}
+	input := append([]*Transaction(nil), merged...)
+
+	result := DedupTransactions(merged)
+	assert.Equal(t, []*Transaction{a, b, c, d, e}, result)
+	assert.Same(t, b, result[1], "the first occurrence must be kept")
+	assert.Same(t, c, result[2], "the first occurrence must be kept")
+	assert.Equal(t, input, merged, "the input must not be modified")
+	assert.Equal(t, result, DedupTransactions(result), "deduplication must be stable")
+}
+
+func TestIteratorSkipsOverlappingPages(t *testing.T) {
+	startTime := time.Date(2023, time.January, 1, 0, 0, 0, 0, time.UTC)
+	transactions := []*Transaction{
+		{UUID: "b", Timestamp: startTime.AddDate(0, 0, 29)},
+		{UUID: "a", Timestamp: startTime.AddDate(0, 0, 29)},
+		{UUID: "c", Timestamp: startTime.AddDate(0, 0, 31)},
+	}
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/transactions" {
+			http.NotFound(w, r)
+			return
+		}
+		// the server returns pages overlapping by two days around their boundaries:
+		query := r.URL.Query()
+		pageStart, err := time.Parse(time.RFC3339, query.Get("start_time"))
+		require.NoError(t, err)
+		pageEnd, err := time.Parse(time.RFC3339, query.Get("end_time"))
+		require.NoError(t, err)
+		page := make([]*Transaction, 0)
+		for _, transaction := range transactions {
+			if transaction.Timestamp.After(pageStart.AddDate(0, 0, -2)) && transaction.Timestamp.Before(pageEnd.AddDate(0, 0, 2)) {
+				page = append(page, transaction)
+			}
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(page))
+	})
+
+	iterator := client.TransactionsIterate(startTime, startTime.AddDate(0, 3, 0))
+	uuids := make([]string, 0)
+	for iterator.Next() {
+		uuids = append(uuids, iterator.Transaction().UUID)
+	}
+	require.NoError(t, iterator.Err())
+	assert.Equal(t, []string{"a", "b", "c"}, uuids)
+}
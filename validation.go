@@ -0,0 +1,114 @@
+package go_groshi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DefaultMaxTimestampSkew is how far in the future a timestamp may be before ValidateTimestamp rejects it.
+// The allowance accounts for clock differences between the client and the server.
+const DefaultMaxTimestampSkew = 5 * time.Minute
+
+// ErrFutureTimestamp is returned when a transaction timestamp is in the future.
+var ErrFutureTimestamp = errors.New("timestamp is in the future")
+
+// ValidateTimestamp returns ErrFutureTimestamp if t is more than DefaultMaxTimestampSkew in the future.
+// Future-dated transactions are usually caused by a typo.
+func ValidateTimestamp(t time.Time) error {
+	return validateTimestamp(t, DefaultMaxTimestampSkew)
+}
+
+func validateTimestamp(t time.Time, maxSkew time.Duration) error {
+	if limit := time.Now().Add(maxSkew); t.After(limit) {
+		return fmt.Errorf("%w: %v is later than %v", ErrFutureTimestamp, t.Format(timeFormat), limit.Format(timeFormat))
+	}
+	return nil
+}
+
+// SetStrict enables or disables strict mode. In strict mode, the client validates
+// arguments before sending requests, so that suspicious data never reaches the server:
+// TransactionsCreate and TransactionsUpdate reject timestamps in the future (see SetMaxTimestampSkew).
+// Strict mode is disabled by default.
+func (c *APIClient) SetStrict(strict bool) {
+	c.strict = strict
+}
+
+// SetMaxTimestampSkew sets how far in the future a timestamp may be before it is rejected in strict mode.
+// The default is DefaultMaxTimestampSkew.
+func (c *APIClient) SetMaxTimestampSkew(d time.Duration) {
+	c.maxTimestampSkew = d
+}
+
+// validateTimestampStrict validates timestamp if strict mode is enabled and timestamp is provided.
+func (c *APIClient) validateTimestampStrict(timestamp *time.Time) error {
+	if !c.strict || timestamp == nil {
+		return nil
+	}
+	return validateTimestamp(*timestamp, c.maxTimestampSkew)
+}
+
+// TransactionsValidate checks whether a transaction could be created from the input, without creating it.
+// The input is checked client-side first (non-zero amount, currency available on the server),
+// then sent to the server with the `validate_only=true` query param, so that the server's own rules are applied.
+// It returns the list of validation problems, which is empty if creating the transaction would succeed,
+// and an error only if the validity could not be determined.
+//
+// Note that the server must support the `validate_only` param: a server ignoring it would create the transaction.
+// For this reason, TransactionsValidate is not available in read-only mode.
+func (c *APIClient) TransactionsValidate(ctx context.Context, input TransactionInput) (problems []string, err error) {
+	if err := c.checkWritable(); err != nil {
+		return nil, err
+	}
+
+	input.Currency = c.currencyOrDefault(input.Currency)
+
+	problems = make([]string, 0)
+	if input.Amount == 0 {
+		problems = append(problems, "amount: must not be zero")
+	}
+	codes, err := c.currencyCodes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := codes[NormalizeCurrency(input.Currency)]; !ok {
+		problems = append(problems, fmt.Sprintf("currency: %q is not available", input.Currency))
+	}
+	if input.Timestamp != nil && c.strict {
+		if err := validateTimestamp(*input.Timestamp, c.maxTimestampSkew); err != nil {
+			problems = append(problems, fmt.Sprintf("timestamp: %v", err))
+		}
+	}
+	if len(problems) > 0 {
+		return problems, nil
+	}
+
+	requestBody := c.newTransactionCreateRequest(input.Amount, input.Currency, input.Description, input.Timestamp)
+
+	var response json.RawMessage
+	err = c.sendRequest(
+		ctx,
+		http.MethodPost,
+		"/transactions",
+		url.Values{"validate_only": {"true"}},
+		requestBody,
+		true,
+		&response,
+	)
+	if err != nil {
+		var apiErr APIError
+		if errors.As(err, &apiErr) &&
+			(apiErr.HTTPStatusCode == http.StatusBadRequest || apiErr.HTTPStatusCode == http.StatusUnprocessableEntity) {
+			if len(apiErr.ErrorDetails) > 0 {
+				return apiErr.ErrorDetails, nil
+			}
+			return []string{apiErr.ErrorMessage}, nil
+		}
+		return nil, err
+	}
+	return problems, nil
+}